@@ -16,12 +16,10 @@ import (
 func customErrorHandler(c *gin.Context, err error) {
 	log.Printf("Error occurred: %v", err)
 
-	statusCode := http.StatusInternalServerError
+	// 根据标准错误设置不同的状态码
+	statusCode := ginserver.StatusFromError(err)
 	code := "INTERNAL_ERROR"
-
-	// 根据错误类型设置不同的状态码
-	if err.Error() == "user not found" {
-		statusCode = http.StatusNotFound
+	if statusCode == http.StatusNotFound {
 		code = "NOT_FOUND"
 	}
 
@@ -40,12 +40,11 @@ type ListUsersRequest struct {
 }
 
 // UpdateArticleRequest 更新文章请求（组合参数）
-// Note: When combining URI + JSON params, avoid using binding validation tags
-// as Gin validates the entire struct after each binding step
+// 所有来源绑定完成后才统一校验，URI 和 JSON 字段可以同时使用 binding 校验标签
 type UpdateArticleRequest struct {
-	ID      int64  `uri:"id" path:"id"` // uri for server, path for client
-	Title   string `json:"title"`       // json body field
-	Content string `json:"content"`     // json body field
+	ID      int64  `uri:"id" path:"id" binding:"required"` // uri for server, path for client
+	Title   string `json:"title" binding:"required"`       // json body field
+	Content string `json:"content"`                        // json body field
 }
 
 // DeleteUserRequest 删除用户请求
@@ -1,13 +1,17 @@
 package store
 
 import (
-	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/zhangzqs/go-typed-rpc/examples/fullstack/model"
+	"github.com/zhangzqs/go-typed-rpc/handler"
 )
 
+// ErrUserNotFound 用户不存在
+var ErrUserNotFound = fmt.Errorf("user %w", handler.ErrNotFound)
+
 // ==================== 模拟数据存储层 ====================
 
 // Store 数据存储
@@ -77,7 +81,7 @@ func (s *Store) GetUser(id int64) (model.User, error) {
 
 	user, exists := s.users[id]
 	if !exists {
-		return model.User{}, errors.New("user not found")
+		return model.User{}, ErrUserNotFound
 	}
 	return user, nil
 }
@@ -101,7 +105,7 @@ func (s *Store) DeleteUser(id int64) error {
 
 	_, exists := s.users[id]
 	if !exists {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 	delete(s.users, id)
 	return nil
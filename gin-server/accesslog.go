@@ -0,0 +1,50 @@
+package ginserver
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// WithAccessLog 在每次请求处理结束后使用 logger 输出一条访问日志
+// 日志包含请求方法、路径、状态码、响应体大小、耗时、请求 ID 和错误，只统计响应体大小而不保存响应体，输入经过 Redact 脱敏后记录：
+// `log:"-"` 字段不会出现在日志中，`redact:"partial"` 字段只保留首尾字符
+// 状态码为 5xx 时使用 Error 级别，4xx 使用 Warn 级别，其余使用 Info 级别
+func WithAccessLog(logger *slog.Logger) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		addExchangeRecorder(opts, func(ex Exchange) {
+			logExchange(logger, ex, opts.tracing)
+		})
+	}
+}
+
+// logExchange 将一次请求处理的记录输出为访问日志
+// 请求 ID 按 tracing 配置的响应头读取，并以其日志属性名记录
+func logExchange(logger *slog.Logger, ex Exchange, tracing handler.TracingConfig) {
+	level := slog.LevelInfo
+	switch {
+	case ex.Status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case ex.Status >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", ex.Request.Method),
+		slog.String("path", ex.Request.URL.Path),
+		slog.Int("status", ex.Status),
+		slog.Int("size", ex.Size),
+		slog.Duration("duration", ex.Duration),
+	}
+	if id := ex.Header.Get(tracing.HeaderName()); id != "" {
+		attrs = append(attrs, slog.String(tracing.LogKeyName(), id))
+	}
+	if ex.Input != nil {
+		attrs = append(attrs, slog.Any("input", Redact(ex.Input)))
+	}
+	if ex.Err != nil {
+		attrs = append(attrs, slog.String("error", ex.Err.Error()))
+	}
+	logger.LogAttrs(ex.Request.Context(), level, "request", attrs...)
+}
@@ -0,0 +1,124 @@
+package ginserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type accessLogRequest struct {
+	Name   string `json:"name"`
+	Token  string `json:"token" redact:"partial"`
+	Secret string `json:"secret" log:"-"`
+}
+
+// TestWithAccessLog tests that access log records contain the redacted input
+func TestWithAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	var recorded []Exchange
+
+	r := gin.New()
+	r.POST("/login", WrapHandler(
+		func(ctx context.Context, req accessLogRequest) (TestResponse, error) {
+			if req.Name == "error" {
+				return TestResponse{}, errors.New("login failed")
+			}
+			return TestResponse{Name: req.Name}, nil
+		},
+		WithRequestID(),
+		WithAccessLog(logger),
+		WithExchangeRecorder(func(ex Exchange) {
+			recorded = append(recorded, ex)
+		}),
+	))
+
+	do := func(body string) map[string]any {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(RequestIDHeader, "req-1")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		return record
+	}
+
+	t.Run("success", func(t *testing.T) {
+		record := do(`{"name":"alice","token":"tok-123456","secret":"s3cret"}`)
+
+		assert.Equal(t, "INFO", record["level"])
+		assert.Equal(t, "request", record["msg"])
+		assert.Equal(t, http.MethodPost, record["method"])
+		assert.Equal(t, "/login", record["path"])
+		assert.Equal(t, float64(http.StatusOK), record["status"])
+		assert.Equal(t, "req-1", record["request_id"])
+		assert.Equal(t, map[string]any{"name": "alice", "token": "t********6"}, record["input"])
+		assert.NotContains(t, buf.String(), "tok-123456")
+		assert.NotContains(t, buf.String(), "s3cret")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		record := do(`{"name":"error","token":"tok-123456"}`)
+
+		assert.Equal(t, "ERROR", record["level"])
+		assert.Equal(t, float64(http.StatusInternalServerError), record["status"])
+		assert.Equal(t, "login failed", record["error"])
+	})
+
+	t.Run("other_recorder_still_called", func(t *testing.T) {
+		assert.Len(t, recorded, 2)
+	})
+}
+
+// TestWithAccessLogSize tests that the access log records the response size without buffering the body
+func TestWithAccessLogSize(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	payload := strings.Repeat("x", 64*1024)
+
+	r := gin.New()
+	r.GET("/download", WrapGetter(
+		func(ctx context.Context) (string, error) {
+			return payload, nil
+		},
+		WithEncoder(func(c *gin.Context, output any) error {
+			// 只记录统计信息时响应体不应被复制到内存缓冲区
+			_, buffered := c.Writer.(*captureWriter)
+			assert.False(t, buffered)
+			c.String(http.StatusOK, output.(string))
+			return nil
+		}),
+		WithAccessLog(logger),
+	))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/download", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, len(payload), w.Body.Len())
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, float64(len(payload)), record["size"])
+
+	// WithExchangeRecorder 仍然保存完整的响应体
+	var recorded Exchange
+	r.GET("/echo", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{Name: "alice"}, nil
+	}, WithAccessLog(logger), WithExchangeRecorder(func(ex Exchange) { recorded = ex })))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/echo", nil))
+	assert.Equal(t, w.Body.String(), string(recorded.Body))
+	assert.Equal(t, w.Body.Len(), recorded.Size)
+}
@@ -0,0 +1,99 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// AsyncTask WrapAsyncAction 受理任务后的响应体，未设置任务 ID 生成器时 TaskID 为空且不输出
+type AsyncTask struct {
+	TaskID string `json:"task_id,omitempty"`
+}
+
+// 错误定义
+var ErrAsyncTaskPanicked = errors.New("async task panicked")
+
+// asyncTaskIDCtxKey 任务 ID 在后台 ctx 中的键
+type asyncTaskIDCtxKey struct{}
+
+// AsyncConfig WrapAsyncAction 的配置，各字段均可为空
+type AsyncConfig struct {
+	// Start 启动检查，在请求 ctx 中同步执行
+	// 返回错误时不启动后台任务，错误交给错误处理器作为本次请求的响应，可用于参数校验、获取锁、检查任务是否已在运行等
+	Start func(ctx context.Context) error
+	// TaskID 任务 ID 生成器，生成的 ID 写入响应体的 task_id 字段，后台 ctx 中可通过 AsyncTaskIDFromContext 获取；
+	// 返回错误时不启动后台任务，错误交给错误处理器
+	TaskID func(ctx context.Context) (string, error)
+	// OnError 后台任务失败时的回调，处理器返回的错误和 panic 都会交给该回调，panic 以 ErrAsyncTaskPanicked 包装；
+	// 为空时使用 slog.Default() 记录错误日志
+	OnError func(ctx context.Context, taskID string, err error)
+}
+
+// AsyncTaskIDFromContext 获取 WrapAsyncAction 后台任务的任务 ID
+func AsyncTaskIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(asyncTaskIDCtxKey{}).(string)
+	return id, ok
+}
+
+// WrapAsyncAction 包装在后台执行的无输入输出处理器，启动后台任务后立即返回 202 Accepted 和 AsyncTask 响应体
+// 处理器在独立的 goroutine 中执行，其 ctx 保留请求 ctx 中的值（如请求 ID、WithBaseContext 注入的值），
+// 但不会随请求结束或客户端断开而取消，需要超时控制时应在处理器内自行设置
+// 错误分为两类：AsyncConfig.Start 和 AsyncConfig.TaskID 返回的启动错误同步交给错误处理器，不会启动后台任务；
+// 后台任务返回的错误和 panic 交给 AsyncConfig.OnError
+// 适用场景：数据同步、报表生成等耗时较长、调用方只需确认任务已受理的场景
+func WrapAsyncAction(
+	h handler.ActionHandlerFunc,
+	config AsyncConfig,
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	onError := config.OnError
+	if onError == nil {
+		onError = func(ctx context.Context, taskID string, err error) {
+			slog.Default().ErrorContext(ctx, "async task failed", "task_id", taskID, "error", err)
+		}
+	}
+
+	return WrapHandler(func(ctx context.Context, _ struct{}) (AsyncTask, error) {
+		if config.Start != nil {
+			if err := config.Start(ctx); err != nil {
+				return AsyncTask{}, err
+			}
+		}
+		var task AsyncTask
+		if config.TaskID != nil {
+			id, err := config.TaskID(ctx)
+			if err != nil {
+				return AsyncTask{}, err
+			}
+			task.TaskID = id
+		}
+
+		// 请求 ctx 在响应写出后被取消，后台任务只继承其中的值
+		bgCtx := context.WithValue(context.WithoutCancel(ctx), asyncTaskIDCtxKey{}, task.TaskID)
+		go runAsyncTask(bgCtx, task.TaskID, h, onError)
+		return task, nil
+	}, append([]WrapHandlerOptionFunc{WithSuccessStatus(http.StatusAccepted)}, options...)...)
+}
+
+// runAsyncTask 执行后台任务，将错误和 panic 交给 onError
+func runAsyncTask(
+	ctx context.Context,
+	taskID string,
+	h handler.ActionHandlerFunc,
+	onError func(ctx context.Context, taskID string, err error),
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			onError(ctx, taskID, fmt.Errorf("%w: %v", ErrAsyncTaskPanicked, r))
+		}
+	}()
+	if err := h(ctx); err != nil {
+		onError(ctx, taskID, err)
+	}
+}
@@ -0,0 +1,136 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// asyncResult 后台任务回调收到的结果
+type asyncResult struct {
+	taskID string
+	err    error
+}
+
+// TestWrapAsyncAction tests running an action in the background and returning 202 immediately
+func TestWrapAsyncAction(t *testing.T) {
+	t.Run("accepted_with_detached_context", func(t *testing.T) {
+		release := make(chan struct{})
+		done := make(chan error, 1)
+		taskIDs := make(chan string, 1)
+
+		r := gin.New()
+		r.POST("/sync", WrapAsyncAction(func(ctx context.Context) error {
+			<-release
+			id, _ := AsyncTaskIDFromContext(ctx)
+			taskIDs <- id
+			// 响应写出后请求 ctx 已结束，后台 ctx 不应被取消
+			done <- ctx.Err()
+			return nil
+		}, AsyncConfig{TaskID: func(ctx context.Context) (string, error) {
+			return "task-1", nil
+		}}))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sync", nil))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.JSONEq(t, `{"task_id":"task-1"}`, w.Body.String())
+
+		close(release)
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+			assert.Equal(t, "task-1", <-taskIDs)
+		case <-time.After(time.Second):
+			t.Fatal("async task did not run")
+		}
+	})
+
+	t.Run("without_task_id", func(t *testing.T) {
+		r := gin.New()
+		r.POST("/sync", WrapAsyncAction(func(ctx context.Context) error {
+			return nil
+		}, AsyncConfig{}))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/sync", nil))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.JSONEq(t, `{}`, w.Body.String())
+	})
+
+	t.Run("start_errors_are_synchronous", func(t *testing.T) {
+		started := false
+		r := gin.New()
+		r.POST("/locked", WrapAsyncAction(func(ctx context.Context) error {
+			started = true
+			return nil
+		}, AsyncConfig{Start: func(ctx context.Context) error {
+			return handler.ErrConflict
+		}}))
+		r.POST("/no-id", WrapAsyncAction(func(ctx context.Context) error {
+			started = true
+			return nil
+		}, AsyncConfig{TaskID: func(ctx context.Context) (string, error) {
+			return "", errors.New("id service unavailable")
+		}}))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/locked", nil))
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/no-id", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "id service unavailable")
+
+		assert.False(t, started)
+	})
+
+	t.Run("background_errors_via_callback", func(t *testing.T) {
+		results := make(chan asyncResult, 2)
+		config := AsyncConfig{
+			TaskID: func(ctx context.Context) (string, error) {
+				return "task-2", nil
+			},
+			OnError: func(ctx context.Context, taskID string, err error) {
+				results <- asyncResult{taskID: taskID, err: err}
+			},
+		}
+
+		r := gin.New()
+		r.POST("/fail", WrapAsyncAction(func(ctx context.Context) error {
+			return errors.New("sync failed")
+		}, config))
+		r.POST("/panic", WrapAsyncAction(func(ctx context.Context) error {
+			panic("boom")
+		}, config))
+
+		for _, path := range []string{"/fail", "/panic"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+			assert.Equal(t, http.StatusAccepted, w.Code)
+
+			select {
+			case res := <-results:
+				assert.Equal(t, "task-2", res.taskID)
+				if path == "/panic" {
+					assert.ErrorIs(t, res.err, ErrAsyncTaskPanicked)
+					assert.Contains(t, res.err.Error(), "boom")
+				} else {
+					assert.EqualError(t, res.err, "sync failed")
+				}
+			case <-time.After(time.Second):
+				t.Fatal("async error handler was not called")
+			}
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package ginserver
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// BindSource 默认解码器的参数来源
+type BindSource int
+
+const (
+	// BindSourceURI 路径参数，对应 `uri` 标签
+	BindSourceURI BindSource = iota
+	// BindSourceHeader 请求头，对应 `header` 标签
+	BindSourceHeader
+	// BindSourceBody 请求体，按 Content-Type 选择 JSON、XML、表单等格式
+	BindSourceBody
+	// BindSourceQuery 查询参数，对应 `form` 标签
+	BindSourceQuery
+)
+
+func (s BindSource) String() string {
+	switch s {
+	case BindSourceURI:
+		return "uri"
+	case BindSourceHeader:
+		return "header"
+	case BindSourceBody:
+		return "body"
+	case BindSourceQuery:
+		return "query"
+	default:
+		return fmt.Sprintf("BindSource(%d)", int(s))
+	}
+}
+
+// DefaultBindSources 默认的参数来源优先级：uri > header > body > query
+// 同一字段可以从多个来源绑定时，优先级高的来源的值生效
+var DefaultBindSources = []BindSource{BindSourceURI, BindSourceHeader, BindSourceBody, BindSourceQuery}
+
+// WithBindSources 设置默认解码器的参数来源及其优先级，排在前面的来源优先级更高
+// 未列出的来源不会被绑定，例如 WithBindSources(BindSourceURI, BindSourceBody) 会忽略请求头和查询参数
+// 未设置时使用 DefaultBindSources
+func WithBindSources(sources ...BindSource) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.bindSources = sources
+	}
+}
+
+// WithURITag 设置默认解码器绑定路径参数时使用的标签名，默认为 uri
+// 适用于结构体已使用 mapstructure 等标签、不希望重复声明 uri 标签的场景
+func WithURITag(name string) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.uriTag = name
+	}
+}
+
+// WithQueryTag 设置默认解码器绑定查询参数时使用的标签名，默认为 form
+// 仅影响查询参数，表单请求体仍使用 form 标签
+func WithQueryTag(name string) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.queryTag = name
+	}
+}
+
+// bindHeader 按 `header` 标签绑定请求头，不触发全局校验
+// 与 gin 的 ShouldBindHeader 一致，标签中的名称不区分大小写
+func bindHeader(ptr any, h http.Header) error {
+	form := make(map[string][]string)
+	collectHeaderValues(form, h, reflect.TypeOf(ptr), map[reflect.Type]bool{})
+	if len(form) == 0 {
+		return nil
+	}
+	return binding.MapFormWithTag(ptr, form, "header")
+}
+
+// collectHeaderValues 收集结构体中 `header` 标签对应的请求头，visiting 用于防止递归类型无限展开
+func collectHeaderValues(form map[string][]string, h http.Header, t reflect.Type, visiting map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("header"), ",")[0]
+		if name == "" {
+			collectHeaderValues(form, h, field.Type, visiting)
+			continue
+		}
+		if values := h.Values(name); len(values) > 0 {
+			form[name] = values
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package ginserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type bindSourceRequest struct {
+	ID     string `uri:"id" header:"X-ID" form:"id" json:"id"`
+	Tenant string `header:"x-tenant" form:"tenant" json:"tenant" binding:"required"`
+	Page   int    `form:"page" json:"page"`
+}
+
+// TestBindSourcesPrecedence tests the precedence when a field is bindable from several sources
+func TestBindSourcesPrecedence(t *testing.T) {
+	newRouter := func(options ...WrapHandlerOptionFunc) *gin.Engine {
+		r := gin.New()
+		h := WrapHandler(func(ctx context.Context, req bindSourceRequest) (bindSourceRequest, error) {
+			return req, nil
+		}, options...)
+		r.POST("/items/:id", h)
+		r.POST("/items", h)
+		return r
+	}
+	do := func(r *gin.Engine, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("default_uri_over_header_over_body_over_query", func(t *testing.T) {
+		r := newRouter()
+		w := do(r, "/items/uri?id=query&tenant=query&page=2", `{"id":"body","tenant":"body","page":1}`,
+			map[string]string{"X-ID": "header", "X-Tenant": "header"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":"uri","tenant":"header","page":1}`, w.Body.String())
+	})
+
+	t.Run("default_header_over_query", func(t *testing.T) {
+		r := newRouter()
+		w := do(r, "/items?id=query&tenant=query", "", map[string]string{"X-ID": "header"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":"header","tenant":"query","page":0}`, w.Body.String())
+	})
+
+	t.Run("custom_order", func(t *testing.T) {
+		r := newRouter(WithBindSources(BindSourceQuery, BindSourceBody, BindSourceHeader, BindSourceURI))
+		w := do(r, "/items/uri?tenant=query&page=2", `{"id":"body","tenant":"body","page":1}`,
+			map[string]string{"X-ID": "header", "X-Tenant": "header"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":"body","tenant":"query","page":2}`, w.Body.String())
+	})
+
+	t.Run("unlisted_source_ignored", func(t *testing.T) {
+		r := newRouter(WithBindSources(BindSourceURI, BindSourceBody))
+		w := do(r, "/items/uri?tenant=query", `{"tenant":"body"}`, map[string]string{"X-Tenant": "header"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":"uri","tenant":"body","page":0}`, w.Body.String())
+	})
+
+	t.Run("validation_after_all_sources", func(t *testing.T) {
+		// 必填字段只由请求头提供时，不应在绑定其他来源时提前校验失败
+		r := newRouter()
+		w := do(r, "/items/uri", `{"page":1}`, map[string]string{"X-Tenant": "header"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = do(r, "/items/uri", `{"page":1}`, nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+// TestWithURITagAndQueryTag tests binding path and query parameters using a custom tag name
+func TestWithURITagAndQueryTag(t *testing.T) {
+	type SearchRequest struct {
+		UserID  int64   `mapstructure:"user_id"`
+		Keyword string  `mapstructure:"q"`
+		Limit   int     `mapstructure:"limit"`
+		MinCost float64 `mapstructure:"min_cost"`
+		Active  bool    `mapstructure:"active"`
+	}
+
+	newRouter := func(options ...WrapHandlerOptionFunc) *gin.Engine {
+		r := gin.New()
+		r.GET("/users/:user_id/search", WrapHandler(
+			func(ctx context.Context, req SearchRequest) (SearchRequest, error) {
+				return req, nil
+			}, options...,
+		))
+		return r
+	}
+
+	t.Run("custom_tags", func(t *testing.T) {
+		r := newRouter(WithURITag("mapstructure"), WithQueryTag("mapstructure"))
+		req := httptest.NewRequest(http.MethodGet, "/users/42/search?q=go&limit=5&min_cost=1.5&active=true", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp SearchRequest
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, SearchRequest{UserID: 42, Keyword: "go", Limit: 5, MinCost: 1.5, Active: true}, resp)
+	})
+
+	t.Run("invalid_path_param", func(t *testing.T) {
+		r := newRouter(WithURITag("mapstructure"))
+		req := httptest.NewRequest(http.MethodGet, "/users/abc/search", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "user_id")
+	})
+
+	t.Run("default_tags_ignore_mapstructure", func(t *testing.T) {
+		r := newRouter()
+		req := httptest.NewRequest(http.MethodGet, "/users/42/search?q=go", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"UserID":0,"Keyword":"","Limit":0,"MinCost":0,"Active":false}`, w.Body.String())
+	})
+}
+
+// TestFormBodySourceExcludesQuery tests that a urlencoded body source does not read URL query parameters
+func TestFormBodySourceExcludesQuery(t *testing.T) {
+	type FormRequest struct {
+		Name  string   `form:"name" json:"name"`
+		Role  string   `form:"role" json:"role"`
+		Tags  []string `form:"tags" json:"tags"`
+		Query string   `q:"role" json:"query"`
+	}
+
+	newRouter := func(options ...WrapHandlerOptionFunc) *gin.Engine {
+		r := gin.New()
+		r.POST("/users/:id", WrapHandler(func(ctx context.Context, req FormRequest) (FormRequest, error) {
+			return req, nil
+		}, options...))
+		return r
+	}
+	do := func(r *gin.Engine, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("body_only", func(t *testing.T) {
+		r := newRouter(WithBindSources(BindSourceURI, BindSourceBody))
+		w := do(r, "/users/1?role=admin&tags=q", "name=alice&tags=b")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"name":"alice","role":"","tags":["b"],"query":""}`, w.Body.String())
+	})
+
+	t.Run("body_over_query_without_merging", func(t *testing.T) {
+		r := newRouter()
+		w := do(r, "/users/1?tags=q", "tags=b")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"name":"","role":"","tags":["b"],"query":""}`, w.Body.String())
+	})
+
+	t.Run("query_uses_query_tag", func(t *testing.T) {
+		r := newRouter(WithQueryTag("q"))
+		w := do(r, "/users/1?role=admin", "name=alice")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"name":"alice","role":"","tags":null,"query":"admin"}`, w.Body.String())
+	})
+}
@@ -0,0 +1,222 @@
+package ginserver
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache 响应缓存接口
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// responseCacheConfig 响应缓存配置
+type responseCacheConfig struct {
+	cache Cache
+	ttl   time.Duration
+}
+
+// WithResponseCache 缓存 GET/HEAD 请求序列化后的响应
+// 缓存键由请求方法、路径和排序后的 Query 参数组成，命中时直接返回缓存内容，不再调用处理器
+// 请求头 Cache-Control: no-cache 可跳过缓存读取（响应仍会写入缓存）；仅缓存 200 响应，
+// 缓存内容包括响应头（如 WithCacheControl 设置的 Cache-Control、处理器设置的 ETag），Set-Cookie 除外
+// 注意：缓存键不包含请求头（包括 Authorization、Cookie、Accept-Language）、c.Keys 以及 `context` 标签注入的值，
+// 按用户或租户返回不同内容的路由使用该选项时，一个用户的响应会被返回给其他用户，只应用于对所有调用方相同的公开数据
+func WithResponseCache(cache Cache, ttl time.Duration) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.responseCache = &responseCacheConfig{cache: cache, ttl: ttl}
+	}
+}
+
+// responseCacheKey 计算请求的缓存键，Query 参数按名称排序以保证稳定
+func responseCacheKey(r *http.Request) string {
+	return r.Method + " " + r.URL.Path + "?" + r.URL.Query().Encode()
+}
+
+// isCacheableRequest 仅缓存安全方法
+func isCacheableRequest(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+// hasNoCacheDirective 判断请求是否要求绕过缓存
+func hasNoCacheDirective(r *http.Request) bool {
+	for _, directive := range strings.Split(r.Header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+			return true
+		}
+	}
+	return false
+}
+
+// uncachedHeaders 不写入缓存的响应头：Set-Cookie 属于单个用户，Content-Length 在写出时重新计算
+var uncachedHeaders = map[string]bool{"Set-Cookie": true, "Content-Length": true}
+
+// encodeCachedResponse 编码缓存条目，格式与 HTTP 报文一致：响应头 + 空行 + 响应体
+func encodeCachedResponse(header http.Header, body []byte) []byte {
+	var buf bytes.Buffer
+	header.WriteSubset(&buf, uncachedHeaders)
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// decodeCachedResponse 解码缓存条目
+func decodeCachedResponse(entry []byte) (http.Header, []byte, bool) {
+	r := bufio.NewReader(bytes.NewReader(entry))
+	header, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, nil, false
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, false
+	}
+	return http.Header(header), body, true
+}
+
+// serve 命中缓存时写出缓存的响应头和响应体并返回 true
+// 本次请求已设置的响应头（如请求 ID、CORS 响应头）保持不变，不会被缓存中的同名响应头覆盖
+func (cfg *responseCacheConfig) serve(c *gin.Context) bool {
+	if hasNoCacheDirective(c.Request) {
+		return false
+	}
+	entry, ok := cfg.cache.Get(responseCacheKey(c.Request))
+	if !ok {
+		return false
+	}
+	header, body, ok := decodeCachedResponse(entry)
+	if !ok {
+		return false
+	}
+	current := c.Writer.Header()
+	for key, values := range header {
+		if _, exists := current[key]; !exists {
+			current[key] = values
+		}
+	}
+	c.Data(http.StatusOK, header.Get("Content-Type"), body)
+	return true
+}
+
+// capture 替换响应写入器以捕获编码后的响应，返回的函数用于在编码完成后写入缓存
+func (cfg *responseCacheConfig) capture(c *gin.Context) func(ok bool) {
+	w := &captureWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	return func(ok bool) {
+		c.Writer = w.ResponseWriter
+		if !ok || w.Status() != http.StatusOK {
+			return
+		}
+		entry := encodeCachedResponse(w.Header(), w.buf.Bytes())
+		cfg.cache.Set(responseCacheKey(c.Request), entry, cfg.ttl)
+	}
+}
+
+// captureWriter 在写入响应的同时保存一份响应体
+type captureWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.buf.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.buf.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// DefaultMemoryCacheEntries NewMemoryCache 默认最多保留的缓存条目数
+const DefaultMemoryCacheEntries = 1000
+
+// MemoryCache 基于内存的 Cache 实现，适用于单实例部署和测试
+// 条目数超出上限时淘汰最久未访问的条目，避免路径和 Query 参数组合过多时内存无限增长；已过期的条目在读取时删除
+type MemoryCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // 元素为 *memoryCacheEntry，最近访问的在前
+}
+
+type memoryCacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// expired 判断条目是否已过期，零值表示永不过期
+func (e *memoryCacheEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// NewMemoryCache 创建最多保留 DefaultMemoryCacheEntries 个条目的内存缓存
+func NewMemoryCache() *MemoryCache {
+	return NewMemoryCacheWithLimit(DefaultMemoryCacheEntries)
+}
+
+// NewMemoryCacheWithLimit 创建最多保留 maxEntries 个条目的内存缓存，maxEntries 小于等于 0 时使用 DefaultMemoryCacheEntries
+func NewMemoryCacheWithLimit(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryCacheEntries
+	}
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryCacheEntry)
+	if entry.expired(time.Now()) {
+		m.remove(elem)
+		return nil, false
+	}
+	m.lru.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryCacheEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	if elem, ok := m.entries[key]; ok {
+		elem.Value = entry
+		m.lru.MoveToFront(elem)
+	} else {
+		m.entries[key] = m.lru.PushFront(entry)
+	}
+	for m.lru.Len() > m.maxEntries {
+		m.remove(m.lru.Back())
+	}
+}
+
+// remove 删除条目
+func (m *MemoryCache) remove(elem *list.Element) {
+	m.lru.Remove(elem)
+	delete(m.entries, elem.Value.(*memoryCacheEntry).key)
+}
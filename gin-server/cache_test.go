@@ -0,0 +1,153 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithResponseCache tests caching serialized responses of GET handlers
+func TestWithResponseCache(t *testing.T) {
+	calls := 0
+	cache := NewMemoryCache()
+
+	r := gin.New()
+	handler := WrapHandler(
+		func(ctx context.Context, req TestQueryRequest) (TestQueryRequest, error) {
+			calls++
+			return req, nil
+		},
+		WithResponseCache(cache, time.Minute),
+	)
+	r.GET("/list", handler)
+	r.POST("/list", handler)
+
+	do := func(method, target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("hit_with_reordered_query", func(t *testing.T) {
+		w := do(http.MethodGet, "/list?page=2&page_size=10", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, calls)
+
+		w = do(http.MethodGet, "/list?page_size=10&page=2", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, calls)
+		assert.JSONEq(t, `{"Page":2,"PageSize":10}`, w.Body.String())
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	})
+
+	t.Run("different_query_misses", func(t *testing.T) {
+		calls = 0
+		do(http.MethodGet, "/list?page=3", nil)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("no_cache_bypasses", func(t *testing.T) {
+		calls = 0
+		do(http.MethodGet, "/list?page=3", http.Header{"Cache-Control": {"no-cache"}})
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("unsafe_method_not_cached", func(t *testing.T) {
+		calls = 0
+		do(http.MethodPost, "/list?page=4", nil)
+		do(http.MethodPost, "/list?page=4", nil)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("hit_replays_headers", func(t *testing.T) {
+		calls = 0
+		r.GET("/etag", WrapHandlerCtx(
+			func(c *gin.Context, ctx context.Context, req TestQueryRequest) (TestQueryRequest, error) {
+				calls++
+				c.Header("ETag", `"v1"`)
+				c.Header("Set-Cookie", "session=alice")
+				return req, nil
+			},
+			WithResponseCache(cache, time.Minute),
+			WithCacheControl(time.Minute, "public"),
+		))
+
+		first := do(http.MethodGet, "/etag?page=1", nil)
+		second := do(http.MethodGet, "/etag?page=1", nil)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusOK, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, `"v1"`, second.Header().Get("ETag"))
+		assert.Equal(t, first.Header().Get("Cache-Control"), second.Header().Get("Cache-Control"))
+		assert.NotEmpty(t, second.Header().Get("Cache-Control"))
+		assert.Contains(t, second.Header().Get("Content-Type"), "application/json")
+		// Set-Cookie 属于单个用户，不应从缓存中返回
+		assert.Empty(t, second.Header().Get("Set-Cookie"))
+	})
+}
+
+// TestMemoryCacheExpiry tests the TTL handling of MemoryCache
+func TestMemoryCacheExpiry(t *testing.T) {
+	cache := NewMemoryCache()
+	cache.Set("a", []byte("1"), time.Millisecond)
+	cache.Set("b", []byte("2"), 0)
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+
+	v, ok := cache.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("2"), v)
+}
+
+// TestMemoryCacheLimit tests that MemoryCache evicts the least recently used entries beyond its limit
+func TestMemoryCacheLimit(t *testing.T) {
+	cache := NewMemoryCacheWithLimit(2)
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Minute)
+
+	// 读取 a 后 b 成为最久未访问的条目
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	cache.Set("c", []byte("3"), time.Minute)
+
+	assert.Equal(t, 2, cache.lru.Len())
+	assert.Contains(t, cache.entries, "a")
+	assert.Contains(t, cache.entries, "c")
+	assert.NotContains(t, cache.entries, "b")
+
+	// 覆盖已有的 key 不增加条目数
+	cache.Set("c", []byte("4"), time.Minute)
+	v, ok := cache.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("4"), v)
+	assert.Equal(t, 2, cache.lru.Len())
+
+	t.Run("many_distinct_keys", func(t *testing.T) {
+		r := gin.New()
+		cache := NewMemoryCacheWithLimit(10)
+		r.GET("/items", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+			return TestResponse{Name: "item"}, nil
+		}, WithResponseCache(cache, time.Minute)))
+
+		for i := range 100 {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items?page="+strconv.Itoa(i), nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+		assert.Equal(t, 10, cache.lru.Len())
+	})
+}
@@ -0,0 +1,41 @@
+package ginserver
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheControlConfig WithCacheControl 的缓存头配置
+type cacheControlConfig struct {
+	maxAge time.Duration
+	value  string
+}
+
+// WithCacheControl 处理成功时在编码器写出响应体之前设置 Cache-Control 和 Expires 响应头，便于 CDN 和浏览器缓存
+// Cache-Control 为 max-age=<秒数> 加上 directives（如 "public"、"stale-while-revalidate=60"），Expires 为当前时间加 maxAge；
+// 处理器已设置 Cache-Control（如 WrapCacheable）时不覆盖；错误响应不携带这两个响应头
+// 适用场景：结果可被共享缓存的 GET 接口
+func WithCacheControl(maxAge time.Duration, directives ...string) WrapHandlerOptionFunc {
+	parts := append([]string{"max-age=" + strconv.FormatInt(int64(max(maxAge, 0)/time.Second), 10)}, directives...)
+	return func(opts *WrapHandlerOptions) {
+		opts.cacheControl = &cacheControlConfig{maxAge: max(maxAge, 0), value: strings.Join(parts, ", ")}
+	}
+}
+
+// apply 写入缓存相关的响应头，返回是否实际写入
+func (cfg *cacheControlConfig) apply(h http.Header) bool {
+	if h.Get("Cache-Control") != "" {
+		return false
+	}
+	h.Set("Cache-Control", cfg.value)
+	h.Set("Expires", time.Now().Add(cfg.maxAge).UTC().Format(http.TimeFormat))
+	return true
+}
+
+// remove 编码失败时移除 apply 写入的响应头
+func (cfg *cacheControlConfig) remove(h http.Header) {
+	h.Del("Cache-Control")
+	h.Del("Expires")
+}
@@ -0,0 +1,55 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// TestWithCacheControl tests declarative Cache-Control and Expires headers on successful responses
+func TestWithCacheControl(t *testing.T) {
+	r := gin.New()
+	r.GET("/articles", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{Name: "article"}, nil
+	}, WithCacheControl(5*time.Minute, "public", "stale-while-revalidate=60")))
+	r.GET("/missing", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{}, handler.ErrNotFound
+	}, WithCacheControl(time.Minute)))
+	r.GET("/hinted", WrapCacheable(func(ctx context.Context, _ struct{}) (TestResponse, CacheHint, error) {
+		return TestResponse{}, CacheHint{Private: true, MaxAge: time.Second}, nil
+	}, WithCacheControl(time.Hour)))
+
+	t.Run("success", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/articles", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "max-age=300, public, stale-while-revalidate=60", w.Header().Get("Cache-Control"))
+		expires, err := http.ParseTime(w.Header().Get("Expires"))
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), expires, 2*time.Second)
+	})
+
+	t.Run("error_not_cached", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("Cache-Control"))
+		assert.Empty(t, w.Header().Get("Expires"))
+	})
+
+	t.Run("handler_hint_wins", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hinted", nil))
+
+		assert.Equal(t, "private, max-age=1", w.Header().Get("Cache-Control"))
+		assert.Empty(t, w.Header().Get("Expires"))
+	})
+}
@@ -0,0 +1,66 @@
+package ginserver
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// WithJSONCodec 设置默认编码器和默认解码器使用的 JSON 序列化函数，可替换为 sonic、jsoniter 等实现
+// 未通过 WithEncoder 自定义编码器时，输出使用 marshal 序列化；
+// 未通过 WithDecoder 自定义解码器时，JSON 请求体使用 unmarshal 反序列化，校验行为不变
+func WithJSONCodec(marshal func(v any) ([]byte, error), unmarshal func(data []byte, v any) error) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.jsonMarshal = marshal
+		opts.jsonUnmarshal = unmarshal
+	}
+}
+
+// jsonCodecEncoder 使用 marshal 序列化输出的编码器，使用 200 状态码
+func jsonCodecEncoder(marshal func(v any) ([]byte, error)) EncoderFunc {
+	return func(c *gin.Context, output any) error {
+		data, err := marshal(output)
+		if err != nil {
+			return err
+		}
+		if c.Request.Method == http.MethodHead {
+			writeHead(c, http.StatusOK, jsonContentType, data)
+			return nil
+		}
+		c.Data(http.StatusOK, jsonContentType, data)
+		return nil
+	}
+}
+
+// codecJSONBinding 使用自定义 unmarshal 的 JSON 绑定，替代 binding.JSON
+type codecJSONBinding struct {
+	unmarshal func(data []byte, v any) error
+}
+
+func (codecJSONBinding) Name() string {
+	return "json"
+}
+
+func (b codecJSONBinding) Bind(req *http.Request, obj any) error {
+	if err := b.decode(req, obj); err != nil {
+		return err
+	}
+	if binding.Validator == nil {
+		return nil
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// decode 读取并反序列化请求体，不进行校验；请求体为空时返回 io.EOF，与 binding.JSON 一致
+func (b codecJSONBinding) decode(req *http.Request, obj any) error {
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return io.EOF
+	}
+	return b.unmarshal(data, obj)
+}
@@ -0,0 +1,92 @@
+package ginserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithJSONCodec tests plugging a custom JSON codec into the default encoder and decoder
+func TestWithJSONCodec(t *testing.T) {
+	var marshaled, unmarshaled int
+	marshal := func(v any) ([]byte, error) {
+		marshaled++
+		return json.Marshal(v)
+	}
+	unmarshal := func(data []byte, v any) error {
+		unmarshaled++
+		return json.Unmarshal(data, v)
+	}
+
+	r := gin.New()
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestResponse, error) {
+			return TestResponse{ID: 1, Name: req.Name, Email: req.Email}, nil
+		},
+		WithJSONCodec(marshal, unmarshal),
+	))
+
+	t.Run("success", func(t *testing.T) {
+		marshaled, unmarshaled = 0, 0
+		body := `{"name":"Alice","email":"alice@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"id":1,"name":"Alice","email":"alice@example.com"}`, w.Body.String())
+		assert.Equal(t, 1, marshaled)
+		assert.Equal(t, 1, unmarshaled)
+	})
+
+	t.Run("validation_still_applies", func(t *testing.T) {
+		marshaled, unmarshaled = 0, 0
+		body := `{"name":"Alice"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "Email")
+		assert.Equal(t, 1, unmarshaled)
+	})
+}
+
+// BenchmarkWrapHandlerJSONCodec benchmarks WrapHandler with a custom JSON codec
+// 将 json.Marshal/json.Unmarshal 替换为 sonic 等实现即可与 BenchmarkWrapHandler 对比
+func BenchmarkWrapHandlerJSONCodec(b *testing.B) {
+	r := gin.New()
+
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestResponse, error) {
+			return TestResponse{
+				ID:    1,
+				Name:  req.Name,
+				Email: req.Email,
+			}, nil
+		},
+		WithJSONCodec(json.Marshal, json.Unmarshal),
+	))
+
+	body := `{"name":"Alice","email":"alice@example.com"}`
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+	}
+}
@@ -0,0 +1,112 @@
+package ginserver
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 错误定义
+var ErrMissingContextValue = errors.New("missing context value")
+var ErrContextValueType = errors.New("context value type mismatch")
+
+// ContextValueError 从 gin.Context 绑定 `context` 标签字段失败时的错误
+// 通常说明中间件没有按预期写入值，默认错误处理器返回 500
+type ContextValueError struct {
+	Key   string // c.Keys 中的 key
+	Field string // 结构体字段名
+	Err   error  // ErrMissingContextValue 或 ErrContextValueType
+}
+
+func (e *ContextValueError) Error() string {
+	return fmt.Sprintf("context key %q for field %s: %v", e.Key, e.Field, e.Err)
+}
+
+func (e *ContextValueError) Unwrap() error {
+	return e.Err
+}
+
+// bindContextValues 将中间件通过 c.Set 写入的值绑定到带有 `context:"key"` 标签的字段
+// 值的类型需可赋值给字段类型（指针字段也可接收其元素类型的值）；
+// key 不存在时字段为零值（不会保留客户端通过其他来源传入的值），字段带有 `binding:"required"` 时返回 ErrMissingContextValue
+func bindContextValues(c *gin.Context, ptr any) error {
+	v := reflect.ValueOf(ptr)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	return bindContextStruct(c, v)
+}
+
+// bindContextStruct 绑定结构体中的 `context` 字段，匿名结构体字段递归处理
+func bindContextStruct(c *gin.Context, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if field.Anonymous && fv.Kind() == reflect.Struct {
+			if err := bindContextStruct(c, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := field.Tag.Get("context")
+		if key == "" || key == "-" {
+			continue
+		}
+		value, ok := c.Get(key)
+		if !ok || value == nil {
+			if isRequiredField(field) {
+				return &ContextValueError{Key: key, Field: field.Name, Err: ErrMissingContextValue}
+			}
+			// 字段只接收中间件写入的值，清除其他来源按字段名绑定的值
+			fv.Set(reflect.Zero(fv.Type()))
+			continue
+		}
+		if !setContextValue(fv, reflect.ValueOf(value)) {
+			return &ContextValueError{
+				Key:   key,
+				Field: field.Name,
+				Err:   fmt.Errorf("%w: %T is not assignable to %s", ErrContextValueType, value, field.Type),
+			}
+		}
+	}
+	return nil
+}
+
+// setContextValue 将 value 赋给字段，字段为指针时可接收其元素类型的值
+func setContextValue(field, value reflect.Value) bool {
+	if value.Type().AssignableTo(field.Type()) {
+		field.Set(value)
+		return true
+	}
+	if field.Kind() == reflect.Ptr && value.Type().AssignableTo(field.Type().Elem()) {
+		p := reflect.New(field.Type().Elem())
+		p.Elem().Set(value)
+		field.Set(p)
+		return true
+	}
+	return false
+}
+
+// isRequiredField 判断字段是否带有 required 校验规则
+func isRequiredField(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type contextUser struct {
+	ID   int64
+	Name string
+}
+
+type contextRequest struct {
+	UserID int64        `context:"userID" binding:"required"`
+	User   *contextUser `context:"user"`
+	Role   string       `context:"role"`
+	Title  string       `json:"title"`
+}
+
+// TestContextValueBinding tests binding values set by middleware into tagged fields
+func TestContextValueBinding(t *testing.T) {
+	newRouter := func(keys map[string]any) *gin.Engine {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			for k, v := range keys {
+				c.Set(k, v)
+			}
+		})
+		r.POST("/posts", WrapHandler(func(ctx context.Context, req contextRequest) (contextRequest, error) {
+			return req, nil
+		}))
+		return r
+	}
+	do := func(r *gin.Engine, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("bound", func(t *testing.T) {
+		r := newRouter(map[string]any{"userID": int64(7), "user": contextUser{ID: 7, Name: "Alice"}, "role": "admin"})
+
+		w := do(r, `{"title":"hello"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"UserID":7,"User":{"ID":7,"Name":"Alice"},"Role":"admin","title":"hello"}`, w.Body.String())
+	})
+
+	t.Run("not_overridable_by_client", func(t *testing.T) {
+		r := newRouter(map[string]any{"userID": int64(7)})
+
+		w := do(r, `{"UserID":1,"Role":"admin","title":"hello"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"UserID":7,"User":null,"Role":"","title":"hello"}`, w.Body.String())
+	})
+
+	t.Run("missing_required", func(t *testing.T) {
+		r := newRouter(nil)
+
+		w := do(r, `{"title":"hello"}`)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `context key \"userID\" for field UserID: missing context value`)
+	})
+
+	t.Run("type_mismatch", func(t *testing.T) {
+		r := newRouter(map[string]any{"userID": "7"})
+
+		w := do(r, `{"title":"hello"}`)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "context value type mismatch")
+	})
+}
@@ -0,0 +1,95 @@
+package ginserver
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig 单个路由的跨域配置
+type CORSConfig struct {
+	AllowOrigins     []string      // 允许的来源，包含 "*" 时允许任意来源
+	AllowMethods     []string      // 预检请求返回的允许方法，为空时使用 GET、POST、HEAD
+	AllowHeaders     []string      // 预检请求返回的允许请求头，为空时回显预检请求的 Access-Control-Request-Headers
+	ExposeHeaders    []string      // 允许浏览器读取的响应头
+	AllowCredentials bool          // 是否允许携带 Cookie 等凭证，开启后不会返回通配的 "*"
+	MaxAge           time.Duration // 预检结果的缓存时间，为 0 时不设置
+}
+
+// WithCORS 为单个路由设置跨域响应头，只对需要跨域访问的接口开放，不影响应用的其余路由
+// 来源被允许时在解码和编码之前写入 Access-Control-Allow-Origin 等响应头，错误响应同样携带；来源不被允许时不写入任何跨域响应头
+// OPTIONS 预检请求直接返回 204 和允许的方法、请求头，不再执行解码器和处理器；来源不被允许的预检请求返回 403。
+// 预检请求只会到达注册了 OPTIONS 方法的路由，需要同时注册，如 r.Match([]string{"GET", "OPTIONS"}, path, h)
+// 注意：与全局 CORS 中间件同时使用时，本选项写入的响应头会覆盖中间件设置的同名响应头；
+// 若全局中间件自行终止了预检请求，预检不会到达本路由，建议不要对同一路由同时使用两者
+func WithCORS(config CORSConfig) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.cors = &config
+	}
+}
+
+// handleCORS 写入跨域响应头，已处理预检请求或拒绝请求时返回 true
+func (cfg *CORSConfig) handleCORS(c *gin.Context) bool {
+	origin := c.GetHeader("Origin")
+	if origin == "" {
+		return false
+	}
+	preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+	h := c.Writer.Header()
+	h.Add("Vary", "Origin")
+	if !cfg.allowOrigin(origin) {
+		if preflight {
+			c.AbortWithStatus(http.StatusForbidden)
+			return true
+		}
+		return false
+	}
+
+	if slices.Contains(cfg.AllowOrigins, "*") && !cfg.AllowCredentials {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if cfg.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+
+	if !preflight {
+		if len(cfg.ExposeHeaders) > 0 {
+			h.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposeHeaders, ", "))
+		}
+		return false
+	}
+
+	methods := cfg.AllowMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodPost, http.MethodHead}
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	if len(cfg.AllowHeaders) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowHeaders, ", "))
+	} else if requested := c.GetHeader("Access-Control-Request-Headers"); requested != "" {
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Headers", requested)
+	}
+	if cfg.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge/time.Second)))
+	}
+	c.AbortWithStatus(http.StatusNoContent)
+	return true
+}
+
+// allowOrigin 判断来源是否被允许
+func (cfg *CORSConfig) allowOrigin(origin string) bool {
+	for _, allowed := range cfg.AllowOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
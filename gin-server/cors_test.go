@@ -0,0 +1,117 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithCORS tests per-route CORS headers and preflight handling
+func TestWithCORS(t *testing.T) {
+	var calls int
+	cors := WithCORS(CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com"},
+		AllowMethods:     []string{http.MethodGet, http.MethodPut},
+		AllowHeaders:     []string{"Authorization", "Content-Type"},
+		ExposeHeaders:    []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+
+	r := gin.New()
+	r.Match([]string{http.MethodGet, http.MethodOptions}, "/public", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		calls++
+		return TestResponse{Name: "public"}, nil
+	}, cors))
+	r.GET("/failing", WrapAction(func(ctx context.Context) error {
+		return errors.New("boom")
+	}, cors))
+	r.GET("/open", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{Name: "open"}, nil
+	}, WithCORS(CORSConfig{AllowOrigins: []string{"*"}})))
+	r.GET("/private", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{Name: "private"}, nil
+	}))
+
+	serve := func(method, path, origin string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("allowed_origin", func(t *testing.T) {
+		w := serve(http.MethodGet, "/public", "https://app.example.com", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "X-Request-ID", w.Header().Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("error_response", func(t *testing.T) {
+		w := serve(http.MethodGet, "/failing", "https://app.example.com", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("disallowed_origin", func(t *testing.T) {
+		w := serve(http.MethodGet, "/public", "https://evil.example.com", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		calls = 0
+		w := serve(http.MethodOptions, "/public", "https://app.example.com", http.Header{
+			"Access-Control-Request-Method":  {http.MethodPut},
+			"Access-Control-Request-Headers": {"Authorization"},
+		})
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, 0, calls)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, PUT", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Authorization, Content-Type", w.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("preflight_disallowed_origin", func(t *testing.T) {
+		w := serve(http.MethodOptions, "/public", "https://evil.example.com", http.Header{
+			"Access-Control-Request-Method": {http.MethodPut},
+		})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("wildcard_origin", func(t *testing.T) {
+		w := serve(http.MethodGet, "/open", "https://any.example.com", nil)
+
+		assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("other_routes_unaffected", func(t *testing.T) {
+		w := serve(http.MethodGet, "/private", "https://app.example.com", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
@@ -0,0 +1,32 @@
+package ginserver
+
+import (
+	"net/http"
+	"time"
+)
+
+// deprecationConfig 路由的弃用信息
+type deprecationConfig struct {
+	sunset time.Time
+	link   string
+}
+
+// WithDeprecation 将路由标记为已弃用，该路由的所有响应（包括错误响应）都会携带弃用相关的响应头
+// 设置 Deprecation: true；sunset 非零时设置 Sunset（HTTP 日期格式），表示路由计划下线的时间；
+// link 非空时追加 Link: <link>; rel="deprecation"，指向迁移说明文档
+func WithDeprecation(sunset time.Time, link string) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.deprecation = &deprecationConfig{sunset: sunset, link: link}
+	}
+}
+
+// apply 写入弃用相关的响应头
+func (d *deprecationConfig) apply(h http.Header) {
+	h.Set("Deprecation", "true")
+	if !d.sunset.IsZero() {
+		h.Set("Sunset", d.sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.link != "" {
+		h.Add("Link", "<"+d.link+`>; rel="deprecation"`)
+	}
+}
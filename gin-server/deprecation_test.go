@@ -0,0 +1,55 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithDeprecation tests the deprecation headers on responses of a deprecated route
+func TestWithDeprecation(t *testing.T) {
+	sunset := time.Date(2027, time.January, 31, 23, 59, 59, 0, time.FixedZone("CST", 8*3600))
+	r := gin.New()
+	r.GET("/v1/users", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{Name: "ok"}, nil
+	}, WithDeprecation(sunset, "https://example.com/migrate-to-v2")))
+	r.GET("/v1/fail", WrapAction(func(ctx context.Context) error {
+		return errors.New("failed")
+	}, WithDeprecation(time.Time{}, "")))
+	r.GET("/v2/users", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{Name: "ok"}, nil
+	}))
+
+	t.Run("deprecated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "true", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Sun, 31 Jan 2027 15:59:59 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, `<https://example.com/migrate-to-v2>; rel="deprecation"`, w.Header().Get("Link"))
+	})
+
+	t.Run("error_response_without_sunset", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/fail", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "true", w.Header().Get("Deprecation"))
+		assert.Empty(t, w.Header().Get("Sunset"))
+		assert.Empty(t, w.Header().Get("Link"))
+	})
+
+	t.Run("not_deprecated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/users", nil))
+
+		assert.Empty(t, w.Header().Get("Deprecation"))
+	})
+}
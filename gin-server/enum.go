@@ -0,0 +1,356 @@
+package ginserver
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	anyType           = reflect.TypeOf((*any)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// enumFieldCache 缓存结构体类型参与 JSON 序列化的字段
+var enumFieldCache sync.Map
+
+// enumTypeCache 缓存类型中是否包含需要转换的枚举字段
+var enumTypeCache sync.Map
+
+// EnumStringEncoder 枚举字符串编码器
+// 对带有 `enum:"true"` 标签且实现了 fmt.Stringer 的字段，输出其 String() 结果而非原始值
+// 支持嵌套结构体、指针、切片、数组、map 和嵌入结构体，值为 nil 的枚举指针字段输出 null，字段名、omitempty 和嵌入字段的展开规则与 encoding/json 一致，其余行为与 DefaultEncoder 一致
+func EnumStringEncoder[O any]() EncoderFunc {
+	return func(c *gin.Context, output any) error {
+		c.JSON(http.StatusOK, stringifyEnums(output))
+		return nil
+	}
+}
+
+// stringifyEnums 将输出中的枚举字段转换为字符串形式，没有需要转换的字段时原样返回
+func stringifyEnums(output any) any {
+	if output == nil {
+		return nil
+	}
+	return convertEnums(reflect.ValueOf(output))
+}
+
+// isEnumField 判断字段是否需要按枚举字符串输出
+func isEnumField(field reflect.StructField) bool {
+	return field.Tag.Get("enum") == "true" && field.Type.Implements(stringerType)
+}
+
+// enumField 结构体中参与 JSON 序列化的字段，嵌入结构体的字段已展开
+type enumField struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+	enum      bool
+}
+
+// enumFields 按 encoding/json 的规则计算结构体参与序列化的字段：
+// 展开未指定 JSON 名称的嵌入结构体（包括未导出的嵌入结构体的导出字段），同名字段取层级最浅的，
+// 同一层级只有一个带 json 标签的字段时取该字段，否则全部忽略
+func enumFields(t reflect.Type) []enumField {
+	if cached, ok := enumFieldCache.Load(t); ok {
+		return cached.([]enumField)
+	}
+
+	type queued struct {
+		typ   reflect.Type
+		index []int
+	}
+	var fields []enumField
+	visited := map[reflect.Type]bool{}
+	next := []queued{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		for _, q := range current {
+			if visited[q.typ] {
+				continue
+			}
+			visited[q.typ] = true
+
+			for i := 0; i < q.typ.NumField(); i++ {
+				sf := q.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					// 未导出的嵌入结构体仍会展开其导出字段
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(append([]int(nil), q.index...), i)
+
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, queued{typ: ft, index: index})
+					continue
+				}
+				f := enumField{
+					name:      name,
+					index:     index,
+					typ:       sf.Type,
+					tagged:    name != "",
+					omitEmpty: hasTagOption(opts, "omitempty"),
+					enum:      isEnumField(sf),
+				}
+				if f.name == "" {
+					f.name = sf.Name
+				}
+				switch ft.Kind() {
+				case reflect.Bool, reflect.String,
+					reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+					reflect.Float32, reflect.Float64:
+					f.quoted = !f.enum && hasTagOption(opts, "string")
+				}
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	fields = dominantEnumFields(fields)
+	enumFieldCache.Store(t, fields)
+	return fields
+}
+
+// dominantEnumFields 处理同名字段，并按字段在结构体中的顺序排序
+func dominantEnumFields(fields []enumField) []enumField {
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) < len(b.index)
+		}
+		return a.tagged && !b.tagged
+	})
+
+	out := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		first := fields[i]
+		if j-i == 1 || len(fields[i+1].index) > len(first.index) || (first.tagged && !fields[i+1].tagged) {
+			out = append(out, first)
+		}
+		i = j
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].index, out[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return out
+}
+
+// hasTagOption 判断 json 标签的选项中是否包含 option
+func hasTagOption(opts, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// hasEnums 判断类型中是否包含需要转换的枚举字段，自定义了 JSON 序列化的类型不做转换
+func hasEnums(t reflect.Type) bool {
+	if cached, ok := enumTypeCache.Load(t); ok {
+		return cached.(bool)
+	}
+	result := buildHasEnums(t, map[reflect.Type]bool{})
+	enumTypeCache.Store(t, result)
+	return result
+}
+
+// buildHasEnums 递归检查类型，visiting 用于防止递归类型无限展开
+func buildHasEnums(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] || t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return buildHasEnums(t.Elem(), visiting)
+	case reflect.Struct:
+		visiting[t] = true
+		defer delete(visiting, t)
+		for _, f := range enumFields(t) {
+			if f.enum || buildHasEnums(f.typ, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// convertEnums 将值转换为枚举字段已替换为字符串的 JSON 值
+func convertEnums(v reflect.Value) any {
+	if !hasEnums(v.Type()) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return convertEnums(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = convertEnums(v.Index(i))
+		}
+		return items
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		// 保留原有的键类型，键的序列化规则（整数键、TextMarshaler 键、排序）仍由 encoding/json 处理
+		out := reflect.MakeMapWithSize(reflect.MapOf(v.Type().Key(), anyType), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := convertEnums(iter.Value())
+			out.SetMapIndex(iter.Key(), reflect.ValueOf(&value).Elem())
+		}
+		return out.Interface()
+	case reflect.Struct:
+		var obj enumObject
+		for _, f := range enumFields(v.Type()) {
+			fv, ok := fieldByIndex(v, f.index)
+			if !ok || (f.omitEmpty && isEmptyJSONValue(fv)) {
+				continue
+			}
+			var value any
+			switch {
+			case f.enum:
+				// nil 指针与 encoding/json 一致输出 null
+				if fv.Kind() != reflect.Ptr || !fv.IsNil() {
+					value = fv.Interface().(fmt.Stringer).String()
+				}
+			case f.quoted:
+				value = quotedJSONValue{v: fv.Interface()}
+			default:
+				value = convertEnums(fv)
+			}
+			obj = append(obj, enumObjectField{name: f.name, value: value})
+		}
+		return obj
+	}
+	return v.Interface()
+}
+
+// fieldByIndex 按下标取出嵌套字段，经过 nil 的嵌入指针时返回 false
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyJSONValue 与 encoding/json 的 omitempty 判断一致
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	}
+	return false
+}
+
+// enumObjectField 转换后结构体的一个字段
+type enumObjectField struct {
+	name  string
+	value any
+}
+
+// enumObject 按字段顺序序列化为 JSON 对象的转换结果
+type enumObject []enumObjectField
+
+func (o enumObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// quotedJSONValue 带有 `json:",string"` 选项的字段，序列化结果再编码为 JSON 字符串
+type quotedJSONValue struct {
+	v any
+}
+
+func (q quotedJSONValue) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(q.v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(b))
+}
@@ -0,0 +1,195 @@
+package ginserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type testStatus int
+
+const (
+	testStatusInactive testStatus = iota
+	testStatusActive
+)
+
+func (s testStatus) String() string {
+	switch s {
+	case testStatusActive:
+		return "active"
+	case testStatusInactive:
+		return "inactive"
+	default:
+		return "unknown"
+	}
+}
+
+type testAccount struct {
+	ID     int64      `json:"id"`
+	Status testStatus `json:"status" enum:"true"`
+	Level  testStatus `json:"level"`
+}
+
+// TestEnumStringEncoder tests serializing enum fields by their string names
+func TestEnumStringEncoder(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/account", WrapGetter(
+			func(ctx context.Context) (testAccount, error) {
+				return testAccount{ID: 1, Status: testStatusActive, Level: testStatusActive}, nil
+			},
+			WithEncoder(EnumStringEncoder[testAccount]()),
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/account", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		// 未标记 enum 的字段保持原始整数值
+		assert.JSONEq(t, `{"id":1,"status":"active","level":1}`, w.Body.String())
+	})
+
+	t.Run("nested_slice_and_pointer", func(t *testing.T) {
+		type AccountList struct {
+			Owner *testAccount  `json:"owner"`
+			Items []testAccount `json:"items"`
+		}
+
+		r := gin.New()
+		r.GET("/accounts", WrapGetter(
+			func(ctx context.Context) (AccountList, error) {
+				return AccountList{
+					Owner: &testAccount{ID: 1, Status: testStatusActive},
+					Items: []testAccount{{ID: 2, Status: testStatusInactive}},
+				}, nil
+			},
+			WithEncoder(EnumStringEncoder[AccountList]()),
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"owner":{"id":1,"status":"active","level":0},
+			"items":[{"id":2,"status":"inactive","level":0}]
+		}`, w.Body.String())
+	})
+
+	t.Run("map_and_array", func(t *testing.T) {
+		type AccountIndex struct {
+			ByName map[string]testAccount `json:"by_name"`
+			ByID   map[int]*testAccount   `json:"by_id"`
+			Pinned [2]testAccount         `json:"pinned"`
+		}
+
+		r := gin.New()
+		r.GET("/accounts", WrapGetter(
+			func(ctx context.Context) (AccountIndex, error) {
+				return AccountIndex{
+					ByName: map[string]testAccount{"alice": {ID: 1, Status: testStatusActive}},
+					ByID:   map[int]*testAccount{2: {ID: 2, Status: testStatusInactive}, 3: nil},
+					Pinned: [2]testAccount{{ID: 1, Status: testStatusActive}},
+				}, nil
+			},
+			WithEncoder(EnumStringEncoder[AccountIndex]()),
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"by_name":{"alice":{"id":1,"status":"active","level":0}},
+			"by_id":{"2":{"id":2,"status":"inactive","level":0},"3":null},
+			"pinned":[{"id":1,"status":"active","level":0},{"id":0,"status":"inactive","level":0}]
+		}`, w.Body.String())
+	})
+
+	t.Run("nil_enum_pointer", func(t *testing.T) {
+		type OptionalStatus struct {
+			Status *testStatus `json:"status" enum:"true"`
+			Prev   *testStatus `json:"prev" enum:"true"`
+		}
+
+		active := testStatusActive
+		r := gin.New()
+		r.GET("/status", WrapGetter(
+			func(ctx context.Context) (OptionalStatus, error) {
+				return OptionalStatus{Status: &active}, nil
+			},
+			WithEncoder(EnumStringEncoder[OptionalStatus]()),
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status":"active","prev":null}`, w.Body.String())
+	})
+}
+
+// testAudit 带方法的嵌入类型
+type testAudit struct {
+	CreatedBy string `json:"created_by"`
+}
+
+func (a testAudit) Describe() string { return "created by " + a.CreatedBy }
+
+// testTimestamps 未导出的嵌入结构体
+type testTimestamps struct {
+	UpdatedAt int64 `json:"updated_at"`
+}
+
+// TestEnumStringEncoderEmbedded tests that embedded structs are flattened like encoding/json
+func TestEnumStringEncoderEmbedded(t *testing.T) {
+	type Account struct {
+		ID int64 `json:"id"`
+		testAudit
+		testTimestamps
+		Status testStatus  `json:"status" enum:"true"`
+		Level  testStatus  `json:"level,omitempty"`
+		Owner  *testStatus `json:"owner" enum:"true"`
+		Secret string      `json:"-"`
+	}
+
+	value := Account{
+		ID:             1,
+		testAudit:      testAudit{CreatedBy: "alice"},
+		testTimestamps: testTimestamps{UpdatedAt: 100},
+		Status:         testStatusActive,
+		Secret:         "hidden",
+	}
+
+	r := gin.New()
+	r.GET("/account", WrapGetter(
+		func(ctx context.Context) (Account, error) {
+			return value, nil
+		},
+		WithEncoder(EnumStringEncoder[Account]()),
+	))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/account", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// 嵌入字段按 encoding/json 的规则展开，与未标记 enum 时的字段保持一致
+	assert.Equal(t, `{"id":1,"created_by":"alice","updated_at":100,"status":"active","owner":null}`, w.Body.String())
+
+	plain, err := json.Marshal(value)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1,"created_by":"alice","updated_at":100,"status":1,"owner":null}`, string(plain))
+}
@@ -0,0 +1,107 @@
+package ginserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exchangeCtxKey 当前请求的 Exchange 在 gin.Context 中的 key
+const exchangeCtxKey = "ginserver.exchange"
+
+// Exchange 一次请求处理的完整记录
+type Exchange struct {
+	Request  *http.Request // 原始请求
+	Input    any           // 解码后的输入，解码失败时为 nil
+	Output   any           // 交给编码器的输出（经过 WithResponseTransformer 转换后）
+	Err      error         // 处理过程中交给错误处理器的错误
+	Status   int           // 响应状态码
+	Header   http.Header   // 响应头
+	Body     []byte        // 编码后的响应体，仅 WithExchangeRecorder 复制，WithAccessLog 等只需要统计信息的记录为 nil
+	Size     int           // 写出的响应体字节数
+	Duration time.Duration // 请求处理耗时
+}
+
+// WithExchangeRecorder 在每次请求处理结束后将完整的请求/响应记录交给 record
+// 记录中同时包含解码后的输入和编码前的输出，测试无需再从 httptest.ResponseRecorder 中解析响应体
+// 会复制一份响应体，主要用于测试和调试
+func WithExchangeRecorder(record func(Exchange)) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.exchangeBody = true
+		addExchangeRecorder(opts, record)
+	}
+}
+
+// addExchangeRecorder 追加 Exchange 的记录函数，多个记录函数按添加顺序依次调用
+func addExchangeRecorder(opts *WrapHandlerOptions, record func(Exchange)) {
+	if prev := opts.exchangeRecorder; prev != nil {
+		opts.exchangeRecorder = func(ex Exchange) {
+			prev(ex)
+			record(ex)
+		}
+		return
+	}
+	opts.exchangeRecorder = record
+	opts.observers = append(opts.observers, func(c *gin.Context, err error) {
+		if ex := exchangeFromContext(c); ex != nil {
+			ex.Err = err
+		}
+	})
+}
+
+// startExchange 开始记录本次请求，返回的函数在请求处理结束时调用
+// keepBody 为 false 时只统计响应体大小，不保存响应体，避免 SSE、文件下载等长响应占用内存
+func startExchange(c *gin.Context, record func(Exchange), keepBody bool) (*Exchange, func()) {
+	start := time.Now()
+	ex := &Exchange{Request: c.Request}
+	c.Set(exchangeCtxKey, ex)
+	w := &countingWriter{ResponseWriter: c.Writer}
+	var capture *captureWriter
+	if keepBody {
+		capture = &captureWriter{ResponseWriter: w}
+		c.Writer = capture
+	} else {
+		c.Writer = w
+	}
+
+	return ex, func() {
+		c.Writer = w.ResponseWriter
+		ex.Status = w.Status()
+		ex.Header = w.Header().Clone()
+		if capture != nil {
+			ex.Body = capture.buf.Bytes()
+		}
+		ex.Size = w.size
+		ex.Duration = time.Since(start)
+		record(*ex)
+	}
+}
+
+// countingWriter 统计写出的响应体字节数，不保存响应体
+type countingWriter struct {
+	gin.ResponseWriter
+	size int
+}
+
+func (w *countingWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.size += n
+	return n, err
+}
+
+func (w *countingWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.size += n
+	return n, err
+}
+
+// exchangeFromContext 返回当前请求正在记录的 Exchange
+func exchangeFromContext(c *gin.Context) *Exchange {
+	v, ok := c.Get(exchangeCtxKey)
+	if !ok {
+		return nil
+	}
+	ex, _ := v.(*Exchange)
+	return ex
+}
@@ -0,0 +1,66 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithExchangeRecorder tests capturing the decoded input and encoded output of a request
+func TestWithExchangeRecorder(t *testing.T) {
+	var exchanges []Exchange
+	r := gin.New()
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestResponse, error) {
+			if req.Name == "error" {
+				return TestResponse{}, errors.New("create failed")
+			}
+			return TestResponse{ID: 1, Name: req.Name, Email: req.Email}, nil
+		},
+		WithExchangeRecorder(func(ex Exchange) {
+			exchanges = append(exchanges, ex)
+		}),
+	))
+
+	t.Run("success", func(t *testing.T) {
+		exchanges = nil
+		body := `{"name":"Alice","email":"alice@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Len(t, exchanges, 1)
+		ex := exchanges[0]
+		assert.Equal(t, http.StatusOK, ex.Status)
+		assert.Equal(t, TestRequest{Name: "Alice", Email: "alice@example.com"}, ex.Input)
+		assert.Equal(t, TestResponse{ID: 1, Name: "Alice", Email: "alice@example.com"}, ex.Output)
+		assert.NoError(t, ex.Err)
+		assert.Equal(t, w.Body.String(), string(ex.Body))
+		assert.Contains(t, ex.Header.Get("Content-Type"), "application/json")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		exchanges = nil
+		body := `{"name":"error","email":"alice@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Len(t, exchanges, 1)
+		ex := exchanges[0]
+		assert.Equal(t, http.StatusInternalServerError, ex.Status)
+		assert.EqualError(t, ex.Err, "create failed")
+		assert.Nil(t, ex.Output)
+		assert.JSONEq(t, `{"error":"create failed"}`, string(ex.Body))
+	})
+}
@@ -0,0 +1,181 @@
+package ginservertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+type CallOptions struct {
+	method string
+	path   string
+	params gin.Params
+	header http.Header
+	query  url.Values
+}
+
+type CallOptionFunc func(*CallOptions)
+
+// WithCallMethod 设置 CallHandler 构造的请求方法，默认为 POST
+func WithCallMethod(method string) CallOptionFunc {
+	return func(opts *CallOptions) {
+		opts.method = method
+	}
+}
+
+// WithCallPath 设置 CallHandler 构造的请求路径，默认为 /
+func WithCallPath(path string) CallOptionFunc {
+	return func(opts *CallOptions) {
+		opts.path = path
+	}
+}
+
+// WithCallPathParam 注入路径参数，等价于路由 /:key 匹配到 value
+func WithCallPathParam(key, value string) CallOptionFunc {
+	return func(opts *CallOptions) {
+		opts.params = append(opts.params, gin.Param{Key: key, Value: value})
+	}
+}
+
+// WithCallHeader 追加请求头
+func WithCallHeader(key, value string) CallOptionFunc {
+	return func(opts *CallOptions) {
+		opts.header.Add(key, value)
+	}
+}
+
+// WithCallQuery 追加 Query 参数
+func WithCallQuery(key, value string) CallOptionFunc {
+	return func(opts *CallOptions) {
+		opts.query.Add(key, value)
+	}
+}
+
+// CallError CallHandler 收到错误响应时返回的错误
+type CallError struct {
+	Status  int    // 响应状态码
+	Message string // 错误响应中的错误信息，无法解析时为响应体原文
+	Body    []byte // 原始响应体
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), e.Message)
+}
+
+// Unwrap 按状态码返回 handler 包中对应的标准错误，便于使用 errors.Is 判断，映射与 handler.ErrorFromStatus 相同
+func (e *CallError) Unwrap() error {
+	return handler.ErrorFromStatus(e.Status)
+}
+
+// InvokeHandler 不启动服务直接执行 gin 处理器，返回记录的响应
+// params 为注入的路径参数，req 中的请求头、Query 和请求体原样交给处理器
+func InvokeHandler(h gin.HandlerFunc, req *http.Request, params ...gin.Param) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = params
+	h(c)
+	c.Writer.WriteHeaderNow()
+	return w
+}
+
+// CallHandler 使用类型化的输入调用包装后的处理器，并将响应解码为 O
+// 输入编码为 JSON 请求体（没有导出字段的输入如 struct{} 不携带请求体），路径参数、请求头和 Query 通过选项注入
+// 返回值依次为解码后的输出、响应状态码和错误；状态码 >= 400 时返回 *CallError
+// 适用场景：在单元测试中直接调用 WrapHandler 等包装的处理器，无需启动 httptest.Server 或手工构造请求
+func CallHandler[I, O any](h gin.HandlerFunc, input I, options ...CallOptionFunc) (O, int, error) {
+	var zero O
+	opts := CallOptions{
+		method: http.MethodPost,
+		path:   "/",
+		header: make(http.Header),
+		query:  make(url.Values),
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	var body io.Reader = http.NoBody
+	if hasExportedFields(reflect.TypeOf(input)) {
+		data, err := json.Marshal(input)
+		if err != nil {
+			return zero, 0, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	target := opts.path
+	if len(opts.query) > 0 {
+		target += "?" + opts.query.Encode()
+	}
+	req := httptest.NewRequest(opts.method, target, body)
+	if body != http.NoBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range opts.header {
+		req.Header[key] = values
+	}
+
+	w := InvokeHandler(h, req, opts.params...)
+	if w.Code >= http.StatusBadRequest {
+		return zero, w.Code, newCallError(w.Code, w.Body.Bytes())
+	}
+
+	var output O
+	if w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+			return zero, w.Code, err
+		}
+	}
+	return output, w.Code, nil
+}
+
+// newCallError 从 {"error": ...} 形式的错误响应中解析错误信息
+func newCallError(status int, body []byte) *CallError {
+	e := &CallError{Status: status, Message: string(body), Body: body}
+	var envelope struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(body, &envelope) != nil || len(envelope.Error) == 0 {
+		return e
+	}
+	var msg string
+	if json.Unmarshal(envelope.Error, &msg) == nil {
+		e.Message = msg
+		return e
+	}
+	var detailed struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(envelope.Error, &detailed) == nil && detailed.Message != "" {
+		e.Message = detailed.Message
+	}
+	return e
+}
+
+// hasExportedFields 判断输入是否需要编码为请求体，没有导出字段的结构体（如 struct{}）不携带请求体
+func hasExportedFields(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
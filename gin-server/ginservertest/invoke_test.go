@@ -0,0 +1,104 @@
+package ginservertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	ginserver "github.com/zhangzqs/go-typed-rpc/gin-server"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+type invokeRequest struct {
+	ID     int64  `uri:"id" json:"-"`
+	Tenant string `header:"X-Tenant" json:"-"`
+	Page   int    `form:"page" json:"-"`
+	Name   string `json:"name" binding:"required"`
+}
+
+type invokeURIRequest struct {
+	ID int64 `uri:"id" binding:"required"`
+}
+
+type invokeResponse struct {
+	ID     int64  `json:"id"`
+	Tenant string `json:"tenant"`
+	Page   int    `json:"page"`
+	Name   string `json:"name"`
+}
+
+// TestCallHandler tests invoking a wrapped handler with a typed input and decoding its output
+func TestCallHandler(t *testing.T) {
+	h := ginserver.WrapHandler(func(ctx context.Context, req invokeRequest) (invokeResponse, error) {
+		if req.Name == "missing" {
+			return invokeResponse{}, fmt.Errorf("user %d: %w", req.ID, handler.ErrNotFound)
+		}
+		return invokeResponse{ID: req.ID, Tenant: req.Tenant, Page: req.Page, Name: req.Name}, nil
+	})
+
+	t.Run("success", func(t *testing.T) {
+		out, status, err := CallHandler[invokeRequest, invokeResponse](h, invokeRequest{Name: "Alice"},
+			WithCallMethod(http.MethodPut),
+			WithCallPathParam("id", "7"),
+			WithCallHeader("X-Tenant", "acme"),
+			WithCallQuery("page", "2"),
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, invokeResponse{ID: 7, Tenant: "acme", Page: 2, Name: "Alice"}, out)
+	})
+
+	t.Run("error_response", func(t *testing.T) {
+		_, status, err := CallHandler[invokeRequest, invokeResponse](h, invokeRequest{Name: "missing"},
+			WithCallPathParam("id", "7"),
+		)
+
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.ErrorIs(t, err, handler.ErrNotFound)
+		var callErr *CallError
+		assert.True(t, errors.As(err, &callErr))
+		assert.Equal(t, "user 7: not found", callErr.Message)
+	})
+
+	t.Run("rate_limited", func(t *testing.T) {
+		limited := ginserver.WrapAction(func(ctx context.Context) error {
+			return handler.ErrTooManyRequests
+		})
+
+		_, status, err := CallHandler[struct{}, struct{}](limited, struct{}{})
+
+		assert.Equal(t, http.StatusTooManyRequests, status)
+		assert.ErrorIs(t, err, handler.ErrTooManyRequests)
+	})
+
+	t.Run("no_input", func(t *testing.T) {
+		getter := ginserver.WrapGetter(func(ctx context.Context) (invokeResponse, error) {
+			return invokeResponse{Name: "ok"}, nil
+		})
+
+		out, status, err := CallHandler[struct{}, invokeResponse](getter, struct{}{}, WithCallMethod(http.MethodGet))
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "ok", out.Name)
+	})
+}
+
+// TestInvokeHandler tests running a gin handler against a raw request without a server
+func TestInvokeHandler(t *testing.T) {
+	h := ginserver.WrapConsumer(func(ctx context.Context, req invokeURIRequest) error {
+		return nil
+	})
+
+	w := InvokeHandler(h, httptest.NewRequest(http.MethodDelete, "/users/7", nil), gin.Param{Key: "id", Value: "7"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = InvokeHandler(h, httptest.NewRequest(http.MethodDelete, "/users/abc", nil), gin.Param{Key: "id", Value: "abc"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -1,27 +1,99 @@
 package ginserver
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
 	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/santhosh-tekuri/jsonschema/v6"
 	"github.com/zhangzqs/go-typed-rpc/handler"
+	"golang.org/x/sync/singleflight"
 )
 
 type DecoderFunc func(c *gin.Context) (any, error)
 
 type EncoderFunc func(c *gin.Context, output any) error
 
+// TypedEncoderFunc 接收具体输出类型的编码器
+type TypedEncoderFunc[O any] func(c *gin.Context, output O) error
+
 type ErrorHandlerFunc func(c *gin.Context, err error)
 
+// ErrorHandlerExFunc 可以访问解码后输入的错误处理器，解码失败时 input 为 nil
+type ErrorHandlerExFunc func(c *gin.Context, input any, err error)
+
+// ResponseTransformerFunc 响应转换函数，返回值将替代处理器的输出交给编码器
+type ResponseTransformerFunc func(ctx context.Context, output any) (any, error)
+
+// ErrorObserverFunc 错误观察者，只用于上报错误，不负责写出响应
+type ErrorObserverFunc func(c *gin.Context, err error)
+
 // 错误定义
 var ErrDecoderReturnedWrongType = errors.New("decoder returned wrong type")
+var ErrEncoderReceivedWrongType = errors.New("encoder received wrong type")
+var ErrMalformedRequest = errors.New("malformed request")
+var ErrValidationFailed = errors.New("validation failed")
 
 type WrapHandlerOptions struct {
 	decoder      DecoderFunc
 	encoder      EncoderFunc
 	errorHandler ErrorHandlerFunc
+	observers    []ErrorObserverFunc
+
+	nilSliceAsEmpty     bool
+	limiter             Limiter
+	keyedLimiter        *keyedLimiter
+	jsonSchema          *jsonschema.Schema
+	lenientNumbers      bool
+	responseCache       *responseCacheConfig
+	hmac                *hmacConfig
+	inputCtxKey         any
+	inputPool           bool
+	inputObjects        *sync.Pool // WithInputPool 生效时默认解码器使用的输入对象池，解码结果为 *I
+	acceptLanguage      bool
+	validator           binding.StructValidator
+	transformers        []ResponseTransformerFunc
+	requestID           bool
+	tracing             handler.TracingConfig
+	optionalBody        bool
+	bindSources         []BindSource
+	deprecation         *deprecationConfig
+	cors                *CORSConfig
+	cacheControl        *cacheControlConfig
+	successStatus       int
+	contentTypeSniffing bool
+	polymorphic         []polymorphicConfig
+	storeInput          bool
+	uriTag              string
+	queryTag            string
+	validationWebhook   func(ctx context.Context, input any) error
+	singleflightKey     func(input any) string
+	baseContext         context.Context
+	nilOutputStatus     int
+	inputType           reflect.Type // 处理器的实际输入类型，RegisterService 以 any 包装方法时由 withInputType 设置
+	slowLogThreshold    time.Duration
+	slowLogger          *slog.Logger
+	jsonMarshal         func(v any) ([]byte, error)
+	jsonUnmarshal       func(data []byte, v any) error
+
+	exchangeRecorder func(Exchange)
+	exchangeBody     bool // 是否需要在 Exchange 中保存响应体
 }
 
 type WrapHandlerOptionFunc func(*WrapHandlerOptions)
@@ -38,115 +110,1008 @@ func WithEncoder(encoder EncoderFunc) WrapHandlerOptionFunc {
 	}
 }
 
+// WithTypedEncoder 设置接收具体输出类型 O 的编码器
+// 类型断言由包装器完成，自定义编码器无需再对 any 做断言；类型不匹配时返回 ErrEncoderReceivedWrongType
+func WithTypedEncoder[O any](encoder TypedEncoderFunc[O]) WrapHandlerOptionFunc {
+	return WithEncoder(typedEncoder(encoder))
+}
+
+// typedEncoder 将 TypedEncoderFunc 适配为 EncoderFunc
+func typedEncoder[O any](encoder TypedEncoderFunc[O]) EncoderFunc {
+	return func(c *gin.Context, output any) error {
+		o, ok := output.(O)
+		if !ok {
+			return ErrEncoderReceivedWrongType
+		}
+		return encoder(c, o)
+	}
+}
+
 func WithErrorHandler(errHandler ErrorHandlerFunc) WrapHandlerOptionFunc {
 	return func(opts *WrapHandlerOptions) {
 		opts.errorHandler = errHandler
 	}
 }
 
+// WithErrorHandlerEx 设置可以访问解码后输入的错误处理器，便于记录导致失败的请求参数
+// 解码成功后输入会保存在 gin.Context 中，解码或类型断言失败时 input 为 nil；与 WithErrorHandler 互相覆盖，以后设置的为准
+func WithErrorHandlerEx(errHandler ErrorHandlerExFunc) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.storeInput = true
+		opts.errorHandler = func(c *gin.Context, err error) {
+			input, _ := DecodedInputFromContext(c)
+			errHandler(c, input, err)
+		}
+	}
+}
+
+// WithSingleflight 合并并发的相同请求，key 相同的请求共享同一次处理器执行的结果或错误
+// keyFn 接收解码后的输入，指针输入会先解引用，值和指针输入得到相同的 key；keyFn 为 nil 时使用输入的 JSON 序列化结果作为 key，
+// 返回空字符串表示不合并该请求。每个路由使用独立的合并组
+// 注意：合并的请求共享首个请求的 ctx，首个请求被取消时其余请求也会收到取消错误；输出被多个请求共享，不应在编码前修改
+// 处理器通过 gin.Context 设置的响应头（如 WrapCacheable 的 Cache-Control、WrapHandlerCtx 中设置的响应头）会复制到其余请求的响应，
+// 但直接写出的状态码、响应体以及 c.Set 写入的值只作用于首个请求，这类 WrapHandlerCtx 处理器不应使用该选项
+// 适用场景：开销较大且结果与调用方无关的查询接口
+func WithSingleflight(keyFn func(input any) string) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		if keyFn == nil {
+			keyFn = defaultSingleflightKey
+		}
+		opts.singleflightKey = keyFn
+	}
+}
+
+// NilOutputError WithNilOutputStatus 设置的状态码不小于 400 时，处理器返回 nil 指针输出对应的错误
+// 默认错误处理器使用 Status 作为响应状态码，自定义错误处理器可通过 errors.As 识别并写出自定义的响应体
+type NilOutputError struct {
+	Status int
+}
+
+func (e *NilOutputError) Error() string {
+	return strings.ToLower(http.StatusText(e.Status))
+}
+
+// WithNilOutputStatus 处理器成功但返回 nil 指针（或 nil 接口）输出时，使用 code 代替 200 null 响应
+// code 不小于 400 时（如 404）将 *NilOutputError 交给错误处理器，默认响应体为 {"error":"not found"}，可通过自定义错误处理器修改；
+// 其余状态码（如 204）只写出状态码，不写出响应体。未设置时保持 200 null 的默认行为
+// 适用场景：GetUser 等查询接口以 (nil, nil) 表示资源不存在
+func WithNilOutputStatus(code int) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.nilOutputStatus = code
+	}
+}
+
+// isNilOutput 判断输出是否为 nil 指针或 nil 接口
+func isNilOutput(output any) bool {
+	if output == nil {
+		return true
+	}
+	v := reflect.ValueOf(output)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// WithBaseContext 设置处理器 ctx 的基础 ctx，ctx 中的值（如数据库连接池、功能开关）对每个请求的处理器可见，无需中间件注入
+// 处理器收到的 ctx 优先查找请求 ctx 中的值，找不到时再查找 ctx 中的值
+// 取消语义：仍以请求 ctx 为准，客户端断开或请求超时时处理器的 ctx 被取消；ctx 自身的取消和截止时间不会传递给处理器，
+// 需要在服务关闭时中断处理器时，应通过 http.Server.BaseContext 等方式取消请求 ctx
+func WithBaseContext(ctx context.Context) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.baseContext = ctx
+	}
+}
+
+// baseValueContext 取消和截止时间来自请求 ctx，值在请求 ctx 中找不到时回退到 base
+type baseValueContext struct {
+	context.Context
+	base context.Context
+}
+
+func (c baseValueContext) Value(key any) any {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.base.Value(key)
+}
+
+// WithSlowLog 请求处理总耗时超过 threshold 时使用 logger 输出一条 Warn 级别的结构化日志
+// 日志包含处理器名称（请求方法和路由模板，如 "GET /users/:id"）、实际请求路径、耗时和阈值，不影响正常的响应
+// logger 为 nil 或 threshold 不大于 0 时不做任何处理
+// 适用场景：SLO 监控，发现响应时间超出预算的接口
+func WithSlowLog(threshold time.Duration, logger *slog.Logger) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		if logger == nil || threshold <= 0 {
+			return
+		}
+		opts.slowLogThreshold = threshold
+		opts.slowLogger = logger
+	}
+}
+
+// logSlowRequest 请求处理耗时超过阈值时输出慢请求日志
+func logSlowRequest(c *gin.Context, logger *slog.Logger, threshold time.Duration, start time.Time) {
+	duration := time.Since(start)
+	if duration <= threshold {
+		return
+	}
+	logger.LogAttrs(c.Request.Context(), slog.LevelWarn, "slow request",
+		slog.String("handler", c.Request.Method+" "+c.FullPath()),
+		slog.String("path", c.Request.URL.Path),
+		slog.Duration("duration", duration),
+		slog.Duration("threshold", threshold),
+	)
+}
+
+// defaultSingleflightKey 使用输入的 JSON 序列化结果作为合并 key，无法序列化时不合并
+func defaultSingleflightKey(input any) string {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// singleflightResult 合并执行的结果，header 为处理器通过 gin.Context 设置的响应头
+type singleflightResult[O any] struct {
+	output O
+	header http.Header
+}
+
+// runSingleflight 按 key 合并处理器执行，key 为空时直接执行
+// 首个请求执行处理器期间新增或修改的响应头（如 WrapCacheable 的 Cache-Control）会复制到共享结果的其余请求
+func runSingleflight[I, O any](c *gin.Context, group *singleflight.Group, keyFn func(input any) string, args I, h func(c *gin.Context, args I) (O, error)) (O, error) {
+	input := reflect.ValueOf(&args).Elem()
+	for input.Kind() == reflect.Ptr && !input.IsNil() {
+		input = input.Elem()
+	}
+	key := keyFn(input.Interface())
+	if key == "" {
+		return runWithWorker(c, args, h)
+	}
+
+	leader := false
+	v, err, _ := group.Do(key, func() (any, error) {
+		leader = true
+		before := c.Writer.Header().Clone()
+		output, err := runWithWorker(c, args, h)
+		return singleflightResult[O]{output: output, header: changedHeaders(before, c.Writer.Header())}, err
+	})
+	result, _ := v.(singleflightResult[O])
+	if !leader {
+		for k, values := range result.header {
+			c.Writer.Header()[k] = append([]string(nil), values...)
+		}
+	}
+	return result.output, err
+}
+
+// changedHeaders 返回 after 中相对 before 新增或修改的响应头
+func changedHeaders(before, after http.Header) http.Header {
+	changed := http.Header{}
+	for k, values := range after {
+		if !slices.Equal(before[k], values) {
+			changed[k] = append([]string(nil), values...)
+		}
+	}
+	return changed
+}
+
+// decodedInputCtxKey 解码后的输入在 gin.Context 中的 key，供 WithErrorHandlerEx 和 DecodedInputFromContext 读取
+const decodedInputCtxKey = "ginserver.decodedInput"
+
+// WithDecodedInputInContext 解码成功后将输入写入 gin.Context，错误处理器、观察者和后续中间件可通过 DecodedInputFromContext 读取，
+// 无需重新解码即可记录引发错误的请求；解码失败时不写入
+func WithDecodedInputInContext() WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.storeInput = true
+	}
+}
+
+// DecodedInputFromContext 返回 WithDecodedInputInContext（或 WithErrorHandlerEx）写入的解码后的输入
+// 未启用或解码失败时第二个返回值为 false
+func DecodedInputFromContext(c *gin.Context) (any, bool) {
+	return c.Get(decodedInputCtxKey)
+}
+
+// WithErrorObserver 添加错误观察者
+// 解码、处理、编码等任一环节产生的错误，都会在错误处理器写出响应之前依次通知所有观察者
+// 多次调用时按添加顺序依次执行，适用于将错误上报到 Sentry、APM 等系统，同时保留原有的错误响应
+func WithErrorObserver(observer ErrorObserverFunc) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.observers = append(opts.observers, observer)
+	}
+}
+
+// WithResponseTransformer 添加响应转换函数，在处理器返回之后、编码器执行之前对输出进行加工
+// 转换函数的返回值即为最终编码的内容，返回错误时交给错误处理器
+// 多次调用时按添加顺序依次执行，适用于按调用者角色裁剪敏感字段等通用的字段级处理
+func WithResponseTransformer(transformer ResponseTransformerFunc) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.transformers = append(opts.transformers, transformer)
+	}
+}
+
+// WithInputInContext 将解码后的输入以 key 存入请求的 context
+// 处理器及其调用的观察者、日志组件可通过 ctx.Value(key) 统一获取本次请求的输入
+func WithInputInContext(key any) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.inputCtxKey = key
+	}
+}
+
+// WithOptionalBody 声明请求体是可选的
+// 没有请求体时，解码器跳过所有校验（包括 binding:"required"），处理器收到仅绑定了 URI/Query 参数的输入，其余字段为零值
+// 携带请求体时与默认行为一致；未设置时，没有请求体的请求仍会在绑定 URI/Query 参数时校验整个结构体
+func WithOptionalBody() WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.optionalBody = true
+	}
+}
+
+// WithSuccessStatus 设置处理成功时的响应状态码，编码器写出的 200 会被替换为 status，其他状态码保持不变
+// 适用场景：创建返回 201、异步受理返回 202 等，无需为此自定义编码器
+func WithSuccessStatus(status int) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.successStatus = status
+	}
+}
+
+// WithInputPool 使用 sync.Pool 复用默认解码器的输入对象，减少大结构体输入在热点路径上的内存分配
+// 仅在 I 为结构体（非指针）且未通过 WithDecoder 指定自定义解码器时生效；绑定结果不再经过 any 装箱，
+// 处理器收到的是对象的副本，池中对象在处理器返回后清空并归还，处理器可以继续持有收到的输入
+func WithInputPool() WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.inputPool = true
+	}
+}
+
 // DefaultDecoder 默认解码器
-// 支持多种绑定方式：URI、Query、JSON、Form 等
+// 支持多种绑定方式：URI、Query、JSON、XML（application/xml、text/xml，按 xml 标签绑定）、Form 等
 func DefaultDecoder[I any]() DecoderFunc {
+	return defaultDecoder[I](bindOptions{})
+}
+
+// bindOptions 默认解码器的绑定选项
+type bindOptions struct {
+	validator    binding.StructValidator // 自定义校验器，nil 时使用 gin 的全局校验器
+	optionalBody bool                    // 没有请求体时跳过校验
+	jsonBinding  binding.Binding         // JSON 请求体的绑定方式，nil 时使用 binding.JSON
+	sources      []BindSource            // 参数来源及优先级，nil 时使用 DefaultBindSources
+	sniffJSON    bool                    // Content-Type 缺失或不明确时根据请求体内容识别 JSON
+	polymorphic  []polymorphicConfig     // 按类型标识解码的接口类型字段
+	uriTag       string                  // 路径参数的标签名，为空时使用 uri
+	queryTag     string                  // 查询参数的标签名，为空时使用 form
+}
+
+// defaultDecoder 使用绑定选项 bo 的默认解码器
+func defaultDecoder[I any](bo bindOptions) DecoderFunc {
 	return func(c *gin.Context) (any, error) {
 		var args I
+		if err := bindRequestWith(c, &args, bo); err != nil {
+			return args, classifyBindError(err)
+		}
+		return args, nil
+	}
+}
 
-		// 1. 绑定 URI 参数（仅当有 URI 参数时）
-		if len(c.Params) > 0 {
-			if err := c.ShouldBindUri(&args); err != nil {
-				return args, err
-			}
+// pooledDecoder 从 pool 中取出输入对象进行绑定，成功时返回 *I，由 wrapHandler 在处理器返回后通过 releasePooledInput 归还；
+// 绑定失败时立即归还
+func pooledDecoder[I any](pool *sync.Pool, bo bindOptions) DecoderFunc {
+	return func(c *gin.Context) (any, error) {
+		ptr := pool.Get().(*I)
+		if err := bindRequestWith(c, ptr, bo); err != nil {
+			releasePooledInput(pool, ptr)
+			return nil, classifyBindError(err)
 		}
+		return ptr, nil
+	}
+}
 
-		// 2. 根据 Content-Type 绑定请求体
-		if c.Request.ContentLength > 0 {
-			// 使用 ShouldBind 自动根据 Content-Type 选择绑定方式
-			if err := c.ShouldBind(&args); err != nil {
-				return args, err
-			}
+// releasePooledInput 清空输入对象后放回池中，避免池中对象持有上一次请求的数据
+func releasePooledInput[I any](pool *sync.Pool, ptr *I) {
+	var zero I
+	*ptr = zero
+	pool.Put(ptr)
+}
+
+// classifyBindError 区分绑定错误的类型：请求体或参数无法解析时包装 ErrMalformedRequest（400），
+// 解析成功但未通过校验规则时包装 ErrValidationFailed（422）；原始错误仍可通过 errors.As 取得
+func classifyBindError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var xmlErr *xml.SyntaxError
+	var numErr *strconv.NumError
+	var verrs validator.ValidationErrors
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &verrs):
+		return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.As(err, &xmlErr),
+		errors.As(err, &numErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("%w: %w", ErrMalformedRequest, err)
+	default:
+		return err
+	}
+}
+
+// bindRequestWith 将请求中的各类参数绑定到 ptr 指向的对象，按 bo 调整参数来源和校验行为
+// 带有 `body:"raw"` 标签的 []byte/json.RawMessage 字段接收未解析的原始请求体
+// 带有 `context:"key"` 标签的字段接收中间件通过 c.Set 写入的值
+// 同一字段可从多个来源绑定时按 DefaultBindSources 的优先级（uri > header > body > query）取值
+// 对于 application/x-www-form-urlencoded 请求体，同名参数以请求体为准（与 http.Request.Form 的语义一致）
+// 所有来源绑定完成后使用自定义校验器（未设置时为 gin 的全局校验器）校验；
+// 设置了 optionalBody 且没有请求体时跳过校验
+func bindRequestWith(c *gin.Context, ptr any, bo bindOptions) error {
+	// 0. 读取需要原样保留的请求体（`body:"raw"` 字段），读取后还原供后续绑定使用
+	var rawBody []byte
+	rawFields := rawBodyFields(reflect.TypeOf(ptr))
+	if len(rawFields) > 0 && hasRequestBody(c.Request) {
+		body, err := peekRequestBody(c)
+		if err != nil {
+			return err
 		}
+		rawBody = body
+	}
 
-		// 3. 绑定 Query 参数（仅当有 Query 时）
-		if len(c.Request.URL.Query()) > 0 {
-			if err := c.ShouldBindQuery(&args); err != nil {
-				return args, err
+	// 取出多态字段，避免 gin 绑定接口类型字段失败
+	var polyValues []polymorphicValue
+	if len(bo.polymorphic) > 0 {
+		values, err := extractPolymorphic(c, reflect.TypeOf(ptr), bo.polymorphic)
+		if err != nil {
+			return err
+		}
+		polyValues = values
+	}
+
+	v := bo.validator
+	skipValidation := bo.optionalBody && !hasRequestBody(c.Request)
+
+	// 各来源绑定时均不触发校验，全部绑定完成后统一校验，避免校验到尚未绑定的字段
+	uriTag, queryTag := bo.uriTag, bo.queryTag
+	if uriTag == "" {
+		uriTag = "uri"
+	}
+	if queryTag == "" {
+		queryTag = "form"
+	}
+	bindURI, bindBody, bindQuery := unvalidatedBinders(c, uriTag, queryTag)
+
+	// URI、Header 和 Query 参数只能绑定到结构体字段，切片、map 等输入只绑定请求体
+	bindFields := isStructType(reflect.TypeOf(ptr))
+
+	sources := bo.sources
+	if sources == nil {
+		sources = DefaultBindSources
+	}
+
+	// 1. 按优先级从低到高依次绑定，优先级高的来源覆盖先写入的值
+	for i := len(sources) - 1; i >= 0; i-- {
+		switch sources[i] {
+		case BindSourceURI:
+			if !bindFields || len(c.Params) == 0 {
+				continue
+			}
+			if err := bindURI(ptr); err != nil {
+				if pathErr := findPathParamError(c.Params, reflect.TypeOf(ptr), uriTag); pathErr != nil {
+					return pathErr
+				}
+				return err
+			}
+		case BindSourceHeader:
+			if !bindFields {
+				continue
+			}
+			if err := bindHeader(ptr, c.Request.Header); err != nil {
+				return err
+			}
+		case BindSourceBody:
+			if !hasRequestBody(c.Request) {
+				continue
+			}
+			// 根据 Content-Type 绑定请求体
+			b := bodyBinding(c)
+			if bo.sniffJSON && isAmbiguousContentType(c.ContentType()) {
+				isJSON, err := sniffJSONBody(c)
+				if err != nil {
+					return err
+				}
+				if isJSON {
+					b = binding.JSON
+				}
+			}
+			if b == binding.JSON && bo.jsonBinding != nil {
+				b = bo.jsonBinding
+			}
+			// 长度未知但实际为空的请求体（如空的 chunked 请求体），与没有请求体一样处理
+			if err := bindBody(ptr, b); err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+		case BindSourceQuery:
+			if !bindFields || len(c.Request.URL.Query()) == 0 {
+				continue
+			}
+			if err := bindQuery(ptr); err != nil {
+				return err
 			}
 		}
+	}
 
-		return args, nil
+	// 2. 写入中间件通过 c.Set 设置的值（`context` 标签），客户端传入的参数无法覆盖
+	if bindFields {
+		if err := bindContextValues(c, ptr); err != nil {
+			return err
+		}
 	}
+
+	// 3. 最后写入原始请求体，避免被前面的绑定步骤覆盖
+	if rawBody != nil {
+		setRawBodyFields(ptr, rawFields, rawBody)
+	}
+
+	// 4. 按类型标识写入多态字段
+	if len(polyValues) > 0 {
+		if err := setPolymorphic(ptr, polyValues); err != nil {
+			return err
+		}
+	}
+
+	// 5. 规范化带有 `normalize` 标签的字段，校验的是规范化后的值
+	if fields := normalizeFields(reflect.TypeOf(ptr)); len(fields) > 0 {
+		normalizeValues(ptr, fields)
+	}
+
+	// 6. 校验完整的绑定结果，未设置自定义校验器时与 gin 一致使用全局校验器
+	switch {
+	case skipValidation:
+	case v != nil:
+		return validateStruct(v, ptr)
+	case binding.Validator != nil:
+		return binding.Validator.ValidateStruct(ptr)
+	}
+
+	return nil
+}
+
+// isStructType 判断 t 去掉指针后是否为结构体
+func isStructType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// bodyBinding 根据 Content-Type 选择请求体的绑定方式
+// 在 gin 默认规则的基础上，将 text/json 视为 JSON
+func bodyBinding(c *gin.Context) binding.Binding {
+	if c.ContentType() == "text/json" {
+		return binding.JSON
+	}
+	return binding.Default(c.Request.Method, c.ContentType())
+}
+
+// hasRequestBody 判断请求是否携带请求体
+// ContentLength 为 -1 表示长度未知（如 chunked 传输），此时根据请求方法和 Content-Type 判断
+func hasRequestBody(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return false
+	}
+	if r.ContentLength >= 0 {
+		return r.ContentLength > 0
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return r.Header.Get("Content-Type") != ""
+}
+
+// peekRequestBody 读取完整的请求体并将其还原，后续的绑定步骤仍可再次读取
+func peekRequestBody(c *gin.Context) ([]byte, error) {
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body.Close()
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, err
 }
 
 // DefaultEncoder 默认编码器
 // 自动将响应序列化为 JSON，使用 200 状态码
+// HEAD 请求只写出与 GET 相同的 Content-Type 和 Content-Length，不写出响应体
 func DefaultEncoder[O any]() EncoderFunc {
 	return func(c *gin.Context, output any) error {
+		if c.Request.Method == http.MethodHead {
+			data, err := json.Marshal(output)
+			if err != nil {
+				return err
+			}
+			writeHead(c, http.StatusOK, jsonContentType, data)
+			return nil
+		}
 		c.JSON(http.StatusOK, output)
 		return nil
 	}
 }
 
+// jsonContentType JSON 响应的 Content-Type，与 gin 的 c.JSON 一致
+const jsonContentType = "application/json; charset=utf-8"
+
+// writeHead 写出 HEAD 请求的响应头，Content-Length 为对应 GET 请求响应体的长度
+func writeHead(c *gin.Context, status int, contentType string, body []byte) {
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Length", strconv.Itoa(len(body)))
+	c.Status(status)
+	c.Writer.WriteHeaderNow()
+}
+
 // DefaultErrorHandler 默认错误处理器
-// 所有错误统一返回 500 状态码
+// 通过 errors.Is 将 handler 包中的标准错误映射为对应的状态码，其余错误返回 500 状态码
+// 响应体为 {"error": msg}；错误实现了 handler.Detailer 且有详细信息时，
+// 响应体为 {"error": {"message": msg, "details": [{"field": ..., "issue": ...}]}}
+// 启用 WithRequestID 时，响应体额外包含 trace_id 字段，值与 X-Request-ID 响应头一致
+// 通过 SetExposeInternalErrors(false) 关闭暴露后，5xx 响应的 error 为通用描述
 func DefaultErrorHandler() ErrorHandlerFunc {
+	return DefaultErrorHandlerWithStatus(StatusFromError)
+}
+
+// DefaultErrorHandlerWithStatus 使用自定义状态码的默认错误处理器
+// statusFor 根据错误返回状态码，返回值 <= 0 时使用 500，响应体格式与 DefaultErrorHandler 一致
+func DefaultErrorHandlerWithStatus(statusFor func(error) int) ErrorHandlerFunc {
 	return func(c *gin.Context, err error) {
 		if err == nil {
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		status := statusFor(err)
+		if status <= 0 {
+			status = http.StatusInternalServerError
+		}
+		body := gin.H{"error": errorPayload(err)}
+		if msg, ok := internalErrorPayload(status); ok {
+			body["error"] = msg
+		}
+		if id := RequestIDFromContext(c.Request.Context()); id != "" {
+			body["trace_id"] = id
+		}
+		c.JSON(status, body)
+	}
+}
+
+// inputTypeCtxKey 当前处理器输入类型在 gin.Context 中的 key，用于将校验错误的字段映射为 JSON 字段名
+const inputTypeCtxKey = "ginserver.inputType"
+
+// ValidationErrorHandler 以字段映射的形式返回校验错误的错误处理器
+// 错误为 validator.ValidationErrors 时返回 422，响应体为 {"errors":{"email":"must be a valid email"}}，
+// key 为输入结构体中的 JSON 字段名（嵌套字段以 . 连接）；其余错误交给 DefaultErrorHandler
+func ValidationErrorHandler() ErrorHandlerFunc {
+	fallback := DefaultErrorHandler()
+	return func(c *gin.Context, err error) {
+		var verrs validator.ValidationErrors
+		if !errors.As(err, &verrs) {
+			fallback(c, err)
+			return
+		}
+
+		v, _ := c.Get(inputTypeCtxKey)
+		inputType, _ := v.(reflect.Type)
+		fields := make(map[string]string, len(verrs))
+		for _, fe := range verrs {
+			fields[jsonFieldPath(inputType, fe)] = validationMessage(fe)
+		}
+		body := gin.H{"errors": fields}
+		if id := RequestIDFromContext(c.Request.Context()); id != "" {
+			body["trace_id"] = id
+		}
+		c.JSON(http.StatusUnprocessableEntity, body)
+	}
+}
+
+// jsonFieldPath 将校验错误的结构体字段路径转换为 JSON 字段名路径，无法解析时返回字段名
+func jsonFieldPath(t reflect.Type, fe validator.FieldError) string {
+	if t == nil {
+		return fe.Field()
+	}
+	segments := strings.Split(fe.StructNamespace(), ".")
+	if len(segments) < 2 {
+		return fe.Field()
+	}
+
+	// 第一段为顶层类型名
+	names := make([]string, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		name, index, _ := strings.Cut(segment, "[")
+		if index != "" {
+			index = "[" + index
+		}
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return fe.Field()
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			return fe.Field()
+		}
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "" || jsonName == "-" {
+			jsonName = field.Name
+		}
+		names = append(names, jsonName+index)
+		t = field.Type
 	}
+	return strings.Join(names, ".")
+}
+
+// validationMessage 生成校验规则对应的错误描述
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "url":
+		return "must be a valid URL"
+	case "uuid":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of " + fe.Param()
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "len":
+		return "must have length " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be greater than or equal to " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "lte":
+		return "must be less than or equal to " + fe.Param()
+	default:
+		if fe.Param() != "" {
+			return fmt.Sprintf("failed on the %q rule (%s)", fe.Tag(), fe.Param())
+		}
+		return fmt.Sprintf("failed on the %q rule", fe.Tag())
+	}
+}
+
+// errorPayload 生成错误响应中 error 字段的内容，没有详细信息时保持字符串形式
+func errorPayload(err error) any {
+	var detailer handler.Detailer
+	if errors.As(err, &detailer) {
+		if details := detailer.Details(); len(details) > 0 {
+			return gin.H{"message": err.Error(), "details": details}
+		}
+	}
+	return err.Error()
+}
+
+// StatusFromError 根据 handler 包中的标准错误及本包的错误返回对应的 HTTP 状态码
+// 无法识别的错误返回 500
+func StatusFromError(err error) int {
+	var schemaErr *SchemaValidationError
+	var pathErr *PathParamError
+	var rejectedErr *ValidationRejectedError
+	var nilOutputErr *NilOutputError
+	switch {
+	case errors.As(err, &rejectedErr) && rejectedErr.Status >= 400:
+		return rejectedErr.Status
+	case errors.As(err, &nilOutputErr) && nilOutputErr.Status >= 400:
+		return nilOutputErr.Status
+	case errors.As(err, &schemaErr), errors.Is(err, ErrValidationFailed):
+		return http.StatusUnprocessableEntity
+	case errors.As(err, &pathErr), errors.Is(err, ErrMalformedRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrUnsupportedVersion):
+		return http.StatusNotAcceptable
+	case errors.Is(err, ErrUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return handler.StatusFromError(err)
+	}
+}
+
+// bindOptions 根据选项构造默认解码器使用的绑定选项
+func (opts *WrapHandlerOptions) bindOptions() bindOptions {
+	bo := bindOptions{
+		validator:    opts.validator,
+		optionalBody: opts.optionalBody,
+		sources:      opts.bindSources,
+		sniffJSON:    opts.contentTypeSniffing,
+		polymorphic:  opts.polymorphic,
+		uriTag:       opts.uriTag,
+		queryTag:     opts.queryTag,
+	}
+	if opts.jsonUnmarshal != nil {
+		bo.jsonBinding = codecJSONBinding{unmarshal: opts.jsonUnmarshal}
+	}
+	return bo
 }
 
 func mergeOptions[I, O any](
 	options ...WrapHandlerOptionFunc,
 ) *WrapHandlerOptions {
 	opts := WrapHandlerOptions{
-		decoder:      DefaultDecoder[I](),
-		encoder:      DefaultEncoder[O](),
 		errorHandler: DefaultErrorHandler(),
 	}
 	for _, opt := range options {
 		opt(&opts)
 	}
+	if opts.encoder == nil {
+		if opts.jsonMarshal != nil {
+			opts.encoder = jsonCodecEncoder(opts.jsonMarshal)
+		} else {
+			opts.encoder = DefaultEncoder[O]()
+		}
+	}
+	if opts.inputType == nil {
+		opts.inputType = reflect.TypeOf((*I)(nil)).Elem()
+	}
+	if opts.decoder == nil {
+		bo := opts.bindOptions()
+		switch {
+		case opts.inputType != reflect.TypeOf((*I)(nil)).Elem():
+			// RegisterService 的路由以 any 作为输入类型，按方法参数的实际类型解码
+			opts.decoder = reflectDecoder(opts.inputType, bo)
+		case isRawBodyType(opts.inputType):
+			opts.decoder = RawBodyDecoder[I]()
+		case opts.inputPool && opts.inputType.Kind() == reflect.Struct:
+			opts.inputObjects = &sync.Pool{New: func() any { return new(I) }}
+			opts.decoder = pooledDecoder[I](opts.inputObjects, bo)
+		default:
+			opts.decoder = defaultDecoder[I](bo)
+		}
+	}
+	if len(opts.observers) > 0 {
+		errHandler, observers := opts.errorHandler, opts.observers
+		opts.errorHandler = func(c *gin.Context, err error) {
+			for _, observer := range observers {
+				observer(c, err)
+			}
+			errHandler(c, err)
+		}
+	}
 	return &opts
 }
 
 func WrapHandler[I, O any](
 	h handler.HandlerFunc[I, O],
 	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return wrapHandler(func(c *gin.Context, args I) (O, error) {
+		return h(c.Request.Context(), args)
+	}, options...)
+}
+
+// WrapHandlerCtx 包装需要访问 gin.Context 的处理器
+// 输入仍按常规方式解码，同时将 gin.Context 传给处理器，便于设置响应头、读取文件等
+// 仅作为少数需要 HTTP 细节的处理器的逃生通道，推荐优先使用只依赖 context.Context 的 WrapHandler
+func WrapHandlerCtx[I, O any](
+	h func(c *gin.Context, ctx context.Context, args I) (O, error),
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return wrapHandler(func(c *gin.Context, args I) (O, error) {
+		return h(c, c.Request.Context(), args)
+	}, options...)
+}
+
+// wrapHandler 包装处理器的通用实现
+func wrapHandler[I, O any](
+	h func(c *gin.Context, args I) (O, error),
+	options ...WrapHandlerOptionFunc,
 ) gin.HandlerFunc {
 	opts := mergeOptions[I, O](options...)
 	decoder := opts.decoder
 	encoder := opts.encoder
 	errHandler := opts.errorHandler
+	inputType := opts.inputType
+	var group *singleflight.Group
+	if opts.singleflightKey != nil {
+		group = new(singleflight.Group)
+	}
 
 	return func(c *gin.Context) {
+		if opts.slowLogger != nil {
+			defer logSlowRequest(c, opts.slowLogger, opts.slowLogThreshold, time.Now())
+		}
+
+		if opts.baseContext != nil {
+			c.Request = c.Request.WithContext(baseValueContext{Context: c.Request.Context(), base: opts.baseContext})
+		}
+
+		var ex *Exchange
+		if opts.exchangeRecorder != nil {
+			var finish func()
+			ex, finish = startExchange(c, opts.exchangeRecorder, opts.exchangeBody)
+			defer finish()
+		}
+
+		if opts.requestID {
+			c.Request = withRequestID(c.Writer, c.Request, opts.tracing.HeaderName())
+		}
+
+		if opts.deprecation != nil {
+			opts.deprecation.apply(c.Writer.Header())
+		}
+
+		if opts.cors != nil && opts.cors.handleCORS(c) {
+			return
+		}
+
+		if abortIfMaintenance(c) {
+			return
+		}
+
+		if opts.limiter != nil && !opts.limiter.Allow(c.Request.Context()) {
+			errHandler(c, ErrRateLimited)
+			return
+		}
+
+		if opts.keyedLimiter != nil {
+			if err := opts.keyedLimiter.check(c); err != nil {
+				errHandler(c, err)
+				return
+			}
+		}
+
+		useCache := opts.responseCache != nil && isCacheableRequest(c.Request)
+		if useCache && opts.responseCache.serve(c) {
+			return
+		}
+
+		if opts.hmac != nil {
+			if err := opts.hmac.verify(c); err != nil {
+				errHandler(c, err)
+				return
+			}
+		}
+
+		if opts.jsonSchema != nil {
+			if err := validateJSONSchema(c, opts.jsonSchema, opts.contentTypeSniffing); err != nil {
+				errHandler(c, err)
+				return
+			}
+		}
+
+		if opts.lenientNumbers {
+			if err := coerceLenientBody(c, inputType); err != nil {
+				errHandler(c, err)
+				return
+			}
+		}
+
+		c.Set(inputTypeCtxKey, inputType)
 		argAny, err := decoder(c)
 		if err != nil {
 			errHandler(c, err)
 			return
 		}
 
-		// 类型断言
-		args, ok := argAny.(I)
-		if !ok {
-			errHandler(c, ErrDecoderReturnedWrongType)
-			return
+		// 类型断言；使用输入对象池时解码器返回 *I，处理器收到栈上的副本，避免每次请求将输入装箱到堆上
+		var args I
+		if opts.inputObjects != nil {
+			ptr, ok := argAny.(*I)
+			if !ok {
+				errHandler(c, ErrDecoderReturnedWrongType)
+				return
+			}
+			defer releasePooledInput(opts.inputObjects, ptr)
+			args = *ptr
+		} else {
+			var ok bool
+			if args, ok = argAny.(I); !ok {
+				errHandler(c, ErrDecoderReturnedWrongType)
+				return
+			}
+		}
+
+		if ex != nil {
+			ex.Input = args
+		}
+		if opts.storeInput {
+			c.Set(decodedInputCtxKey, args)
+		}
+
+		// 未设置时跳过，避免每次请求都将输入装箱
+		if opts.validationWebhook != nil {
+			if err := runValidationWebhook(c.Request.Context(), opts.validationWebhook, args); err != nil {
+				errHandler(c, err)
+				return
+			}
+		}
+
+		if opts.acceptLanguage {
+			c.Request = withLanguages(c.Request)
 		}
 
-		output, err := h(c.Request.Context(), args)
+		if opts.inputCtxKey != nil {
+			ctx := context.WithValue(c.Request.Context(), opts.inputCtxKey, args)
+			c.Request = c.Request.WithContext(ctx)
+		}
+
+		var output O
+		if group != nil {
+			output, err = runSingleflight(c, group, opts.singleflightKey, args, h)
+		} else {
+			output, err = runWithWorker(c, args, h)
+		}
 		if err != nil {
 			errHandler(c, err)
 			return
 		}
 
-		if err := encoder(c, output); err != nil {
+		if opts.nilOutputStatus != 0 && isNilOutput(output) {
+			if opts.nilOutputStatus >= http.StatusBadRequest {
+				errHandler(c, &NilOutputError{Status: opts.nilOutputStatus})
+			} else {
+				c.Status(opts.nilOutputStatus)
+				c.Writer.WriteHeaderNow()
+			}
+			return
+		}
+
+		if opts.nilSliceAsEmpty {
+			output = normalizeNilSlices(output)
+		}
+
+		var encoded any = output
+		for _, transform := range opts.transformers {
+			if encoded, err = transform(c.Request.Context(), encoded); err != nil {
+				errHandler(c, err)
+				return
+			}
+		}
+
+		if ex != nil {
+			ex.Output = encoded
+		}
+
+		var store func(ok bool)
+		if useCache {
+			store = opts.responseCache.capture(c)
+		}
+		cacheHeaders := opts.cacheControl != nil && opts.cacheControl.apply(c.Writer.Header())
+		if opts.successStatus != 0 {
+			w := c.Writer
+			c.Writer = &successStatusWriter{ResponseWriter: w, status: opts.successStatus}
+			c.Status(opts.successStatus)
+			err = encoder(c, encoded)
+			c.Writer = w
+		} else {
+			err = encoder(c, encoded)
+		}
+		if store != nil {
+			store(err == nil)
+		}
+		if err != nil {
+			if cacheHeaders && !c.Writer.Written() {
+				opts.cacheControl.remove(c.Writer.Header())
+			}
 			errHandler(c, err)
 			return
 		}
 	}
 }
 
+// successStatusWriter 将编码器写出的 200 状态码替换为 WithSuccessStatus 设置的状态码
+type successStatusWriter struct {
+	gin.ResponseWriter
+	status int
+}
+
+func (w *successStatusWriter) WriteHeader(code int) {
+	if code == http.StatusOK {
+		code = w.status
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
 // WrapAction 包装无输入输出的处理器
+// 选项与 WrapHandler 通用，WithErrorHandler 等选项可直接传入
 // 适用场景：触发任务、执行操作等不需要请求参数和响应数据的场景
 func WrapAction(
 	h handler.ActionHandlerFunc,
@@ -158,6 +1123,7 @@ func WrapAction(
 }
 
 // WrapGetter 包装只有输出的处理器
+// 选项与 WrapHandler 通用，可通过 WithTypedEncoder[O] 直接按输出类型定制编码器
 // 适用场景：获取数据、健康检查等不需要请求参数的查询场景
 func WrapGetter[O any](
 	h handler.GetterHandlerFunc[O],
@@ -178,3 +1144,244 @@ func WrapConsumer[I any](
 		return struct{}{}, h(ctx, args)
 	}, options...)
 }
+
+// WrapCreator 包装创建资源的处理器
+// 成功时返回 201 状态码，并通过 locationFn 根据输出生成 Location 响应头
+// 响应体仍由配置的编码器写出，WithJSONCodec、WithNilSliceAsEmpty 等选项和 HEAD 请求的处理与 WrapHandler 一致
+// 适用场景：POST 创建资源等需要返回新资源地址的场景
+func WrapCreator[I, O any](
+	h handler.HandlerFunc[I, O],
+	locationFn func(O) string,
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return wrapHandler(func(c *gin.Context, args I) (O, error) {
+		o, err := h(c.Request.Context(), args)
+		if err != nil {
+			return o, err
+		}
+		if location := locationFn(o); location != "" {
+			c.Header("Location", location)
+		}
+		return o, nil
+	}, append([]WrapHandlerOptionFunc{WithSuccessStatus(http.StatusCreated)}, options...)...)
+}
+
+// WrapCreated 包装创建资源的处理器，成功时返回 201 状态码，其余行为与 WrapHandler 一致
+// 适用场景：POST 创建资源且不需要 Location 响应头的场景
+func WrapCreated[I, O any](
+	h handler.HandlerFunc[I, O],
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return WrapHandler(h, append([]WrapHandlerOptionFunc{WithSuccessStatus(http.StatusCreated)}, options...)...)
+}
+
+// WrapAccepted 包装异步受理的处理器，成功时返回 202 状态码，其余行为与 WrapHandler 一致
+// 适用场景：提交后台任务等请求已受理但尚未处理完成的场景
+func WrapAccepted[I, O any](
+	h handler.HandlerFunc[I, O],
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return WrapHandler(h, append([]WrapHandlerOptionFunc{WithSuccessStatus(http.StatusAccepted)}, options...)...)
+}
+
+// CacheHint 响应缓存提示，由 WrapCacheable 转换为 Cache-Control 响应头
+type CacheHint struct {
+	MaxAge  time.Duration // 缓存有效期
+	Private bool          // 仅允许客户端缓存，不允许共享缓存（CDN、代理）缓存
+	NoStore bool          // 禁止任何缓存，设置后忽略其他字段
+}
+
+// CacheControl 返回对应的 Cache-Control 值，零值返回空字符串
+func (h CacheHint) CacheControl() string {
+	if h.NoStore {
+		return "no-store"
+	}
+	if h.MaxAge <= 0 && !h.Private {
+		return ""
+	}
+
+	visibility := "public"
+	if h.Private {
+		visibility = "private"
+	}
+	maxAge := int64(h.MaxAge / time.Second)
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	return fmt.Sprintf("%s, max-age=%d", visibility, maxAge)
+}
+
+// WrapCacheable 包装返回缓存提示的处理器
+// 处理器成功时根据 CacheHint 设置 Cache-Control 响应头，编码方式与 WrapHandler 一致
+// 适用场景：需要声明式控制缓存策略的查询接口
+func WrapCacheable[I, O any](
+	h func(ctx context.Context, args I) (O, CacheHint, error),
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return wrapHandler(func(c *gin.Context, args I) (O, error) {
+		output, hint, err := h(c.Request.Context(), args)
+		if err != nil {
+			return output, err
+		}
+		if cacheControl := hint.CacheControl(); cacheControl != "" {
+			c.Header("Cache-Control", cacheControl)
+		}
+		return output, nil
+	}, options...)
+}
+
+// streamOutput 流式响应的内部输出类型
+type streamOutput struct {
+	reader      io.Reader
+	contentType string
+	header      http.Header // 开始写出时追加的响应头，如 Content-Disposition
+}
+
+// writeHeader 开始写出响应体时写入响应头和状态码，写入前出错时错误响应不会携带这些响应头
+func (s streamOutput) writeHeader(c *gin.Context) {
+	if s.contentType != "" {
+		c.Header("Content-Type", s.contentType)
+	}
+	for k, v := range s.header {
+		c.Writer.Header()[k] = v
+	}
+	c.Status(http.StatusOK)
+}
+
+// WrapStreamReader 包装返回 io.Reader 的处理器
+// 处理器返回的 string 为响应的 Content-Type，响应体以 chunked 方式直接拷贝到客户端，不做整体缓冲
+// 若 reader 实现了 io.Closer，写入结束后会自动关闭
+// 写入第一个字节之前的错误交给错误处理器，写入过程中的错误直接终止响应
+// 适用场景：大文件下载、导出等响应体较大的场景
+func WrapStreamReader[I any](
+	h func(ctx context.Context, args I) (io.Reader, string, error),
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return WrapHandler(func(ctx context.Context, args I) (streamOutput, error) {
+		reader, contentType, err := h(ctx, args)
+		if err != nil {
+			if closer, ok := reader.(io.Closer); ok {
+				closer.Close()
+			}
+			return streamOutput{}, err
+		}
+		return streamOutput{reader: reader, contentType: contentType}, nil
+	}, append([]WrapHandlerOptionFunc{WithEncoder(streamReaderEncoder)}, options...)...)
+}
+
+// FileResult 文件下载处理器的返回值
+type FileResult struct {
+	Filename    string    // 下载文件名，写入 Content-Disposition；为空时只设置 attachment
+	ContentType string    // 响应的 Content-Type，为空时使用 application/octet-stream
+	Reader      io.Reader // 文件内容，实现了 io.Seeker 时支持 Range 请求，实现了 io.Closer 时写入结束后自动关闭
+	Size        int64     // 文件大小，大于 0 时设置 Content-Length；Reader 可 Seek 时由 Seek 得到，忽略该字段
+}
+
+// WrapFile 包装返回文件的处理器，以附件形式下载
+// 响应头 Content-Disposition 为 attachment; filename=...（非 ASCII 文件名按 RFC 2231 编码），Size 已知时设置 Content-Length，
+// 文件内容以流式方式拷贝到客户端；处理器的错误以及写出第一个字节之前的读取错误交给错误处理器，且错误响应不携带上述响应头
+// Reader 实现了 io.ReadSeeker 时按 http.ServeContent 的语义处理 Range 请求：返回 Accept-Ranges: bytes，
+// 合法的 Range 返回 206 Partial Content，无法满足的 Range 返回 416；不可 Seek 的 Reader 始终以 200 返回完整内容
+// 适用场景：报表导出、附件下载、支持断点续传的大文件和媒体下载
+func WrapFile[I any](
+	h func(ctx context.Context, args I) (FileResult, error),
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return WrapHandler(func(ctx context.Context, args I) (streamOutput, error) {
+		file, err := h(ctx, args)
+		if err != nil {
+			if closer, ok := file.Reader.(io.Closer); ok {
+				closer.Close()
+			}
+			return streamOutput{}, err
+		}
+
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := http.Header{}
+		disposition := "attachment"
+		if file.Filename != "" {
+			disposition = mime.FormatMediaType("attachment", map[string]string{"filename": file.Filename})
+		}
+		header.Set("Content-Disposition", disposition)
+		if file.Size > 0 {
+			header.Set("Content-Length", strconv.FormatInt(file.Size, 10))
+		}
+		return streamOutput{reader: file.Reader, contentType: contentType, header: header}, nil
+	}, append([]WrapHandlerOptionFunc{WithEncoder(fileEncoder)}, options...)...)
+}
+
+// fileEncoder 文件下载编码器，reader 可 Seek 时交给 http.ServeContent 处理 Range 请求，否则与 streamReaderEncoder 一致
+func fileEncoder(c *gin.Context, output any) error {
+	s, ok := output.(streamOutput)
+	if !ok {
+		return ErrEncoderReceivedWrongType
+	}
+	rs, ok := s.reader.(io.ReadSeeker)
+	if !ok {
+		return streamReaderEncoder(c, output)
+	}
+	if closer, ok := rs.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	h := c.Writer.Header()
+	h.Set("Content-Type", s.contentType)
+	for k, v := range s.header {
+		// Content-Length 由 ServeContent 按实际返回的范围计算
+		if k != "Content-Length" {
+			h[k] = v
+		}
+	}
+	http.ServeContent(c.Writer, c.Request, "", time.Time{}, rs)
+	return nil
+}
+
+// streamReaderEncoder 将 streamOutput 中的 reader 拷贝到响应
+func streamReaderEncoder(c *gin.Context, output any) error {
+	s, ok := output.(streamOutput)
+	if !ok {
+		return ErrEncoderReceivedWrongType
+	}
+	if s.reader == nil {
+		c.Status(http.StatusOK)
+		return nil
+	}
+	if closer, ok := s.reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	buf := make([]byte, 32*1024)
+	written := false
+	for {
+		n, err := s.reader.Read(buf)
+		if n > 0 {
+			if !written {
+				s.writeHeader(c)
+				written = true
+			}
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				// 客户端断开等写入错误，直接终止
+				c.Abort()
+				return nil
+			}
+			c.Writer.Flush()
+		}
+		if err == io.EOF {
+			if !written {
+				s.writeHeader(c)
+			}
+			return nil
+		}
+		if err != nil {
+			if !written {
+				return err
+			}
+			// 已开始写入响应，无法再返回错误状态码，直接终止
+			c.Abort()
+			return nil
+		}
+	}
+}
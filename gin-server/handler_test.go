@@ -1,16 +1,30 @@
 package ginserver
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
 )
 
 func init() {
@@ -85,7 +99,7 @@ func TestWrapHandler(t *testing.T) {
 
 		r.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
 	})
 
 	t.Run("handler_error", func(t *testing.T) {
@@ -157,6 +171,81 @@ func TestWrapGetter(t *testing.T) {
 	})
 }
 
+// TestHeadRequest tests that HEAD mirrors the GET headers without writing a body
+func TestHeadRequest(t *testing.T) {
+	getter := func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{ID: 1, Name: "Alice", Email: "alice@example.com"}, nil
+	}
+
+	for name, options := range map[string][]WrapHandlerOptionFunc{
+		"default_encoder": nil,
+		"json_codec":      {WithJSONCodec(json.Marshal, json.Unmarshal)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := gin.New()
+			h := WrapGetter(getter, options...)
+			r.GET("/users/1", h)
+			r.HEAD("/users/1", h)
+
+			get := httptest.NewRecorder()
+			r.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+			head := httptest.NewRecorder()
+			r.ServeHTTP(head, httptest.NewRequest(http.MethodHead, "/users/1", nil))
+
+			assert.Equal(t, http.StatusOK, head.Code)
+			assert.Empty(t, head.Body.String())
+			assert.Equal(t, get.Header().Get("Content-Type"), head.Header().Get("Content-Type"))
+			assert.Equal(t, strconv.Itoa(get.Body.Len()), head.Header().Get("Content-Length"))
+		})
+	}
+}
+
+// TestXMLRequestBody tests binding application/xml and text/xml bodies using xml tags
+func TestXMLRequestBody(t *testing.T) {
+	type PartnerOrder struct {
+		XMLName  xml.Name `xml:"order"`
+		ID       int64    `uri:"id" xml:"-"`
+		Number   string   `xml:"number" binding:"required"`
+		Quantity int      `xml:"quantity"`
+		Items    []string `xml:"items>item"`
+	}
+
+	var received PartnerOrder
+	r := gin.New()
+	r.POST("/partners/:id/orders", WrapConsumer(func(ctx context.Context, req PartnerOrder) error {
+		received = req
+		return nil
+	}))
+
+	body := `<order><number>PO-1</number><quantity>3</quantity><items><item>a</item><item>b</item></items></order>`
+	for _, contentType := range []string{"application/xml", "text/xml; charset=utf-8"} {
+		t.Run(contentType, func(t *testing.T) {
+			received = PartnerOrder{}
+			req := httptest.NewRequest(http.MethodPost, "/partners/7/orders", strings.NewReader(body))
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, int64(7), received.ID)
+			assert.Equal(t, "PO-1", received.Number)
+			assert.Equal(t, 3, received.Quantity)
+			assert.Equal(t, []string{"a", "b"}, received.Items)
+		})
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/partners/7/orders", strings.NewReader(`<order><quantity>1</quantity></order>`))
+		req.Header.Set("Content-Type", "application/xml")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
 // TestWrapConsumer tests the WrapConsumer functionality
 func TestWrapConsumer(t *testing.T) {
 	r := gin.New()
@@ -247,6 +336,80 @@ func TestWrapAction(t *testing.T) {
 	})
 }
 
+// TestWrapHandlerCtx tests the WrapHandlerCtx functionality
+func TestWrapHandlerCtx(t *testing.T) {
+	r := gin.New()
+
+	r.GET("/users/:id", WrapHandlerCtx(
+		func(c *gin.Context, ctx context.Context, req TestURIRequest) (TestResponse, error) {
+			assert.Equal(t, c.Request.Context(), ctx)
+			c.Header("X-User-ID", c.Param("id"))
+			return TestResponse{ID: req.ID}, nil
+		},
+	))
+
+	t.Run("success", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "7", w.Header().Get("X-User-ID"))
+
+		var resp TestResponse
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), resp.ID)
+	})
+
+	t.Run("decode_error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/abc", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, w.Header().Get("X-User-ID"))
+	})
+}
+
+// TestWithInputInContext tests storing the decoded input in the request context
+func TestWithInputInContext(t *testing.T) {
+	type inputKey struct{}
+
+	logInput := func(ctx context.Context) any {
+		return ctx.Value(inputKey{})
+	}
+
+	var logged any
+	var afterHandler any
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		afterHandler = c.Request.Context().Value(inputKey{})
+	})
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestResponse, error) {
+			logged = logInput(ctx)
+			return TestResponse{Name: req.Name}, nil
+		},
+		WithInputInContext(inputKey{}),
+	))
+
+	body := `{"name":"Alice","email":"alice@example.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	expected := TestRequest{Name: "Alice", Email: "alice@example.com"}
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, expected, logged)
+	assert.Equal(t, expected, afterHandler)
+}
+
 // TestDefaultDecoder tests the default decoder with various binding scenarios
 func TestDefaultDecoder(t *testing.T) {
 	r := gin.New()
@@ -318,6 +481,241 @@ func TestDefaultDecoder(t *testing.T) {
 		assert.Equal(t, 2, resp.Page)
 		assert.Equal(t, 20, resp.PageSize)
 	})
+
+	t.Run("combined_binding_required", func(t *testing.T) {
+		// 校验在所有来源绑定完成后只执行一次，URI 和 JSON 字段上的 required 可以同时生效
+		type UpdateArticleRequest struct {
+			ID    int64  `uri:"id" binding:"required"`
+			Title string `json:"title" binding:"required"`
+			Page  int    `form:"page" binding:"omitempty,gte=1"`
+		}
+		r4 := gin.New()
+		r4.PUT("/articles/:id", WrapHandler(
+			func(ctx context.Context, req UpdateArticleRequest) (UpdateArticleRequest, error) {
+				return req, nil
+			},
+			WithErrorHandler(ValidationErrorHandler()),
+		))
+
+		serve := func(path, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPut, path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r4.ServeHTTP(w, req)
+			return w
+		}
+
+		w := serve("/articles/5?page=1", `{"title":"Hello"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"ID":5,"title":"Hello","Page":1}`, w.Body.String())
+
+		w = serve("/articles/5", `{}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), `"title"`)
+		assert.NotContains(t, w.Body.String(), `"ID"`)
+	})
+}
+
+// TestDefaultDecoderChunkedBody tests binding a body with unknown content length
+func TestDefaultDecoderChunkedBody(t *testing.T) {
+	r := gin.New()
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestRequest, error) {
+			return req, nil
+		},
+	))
+
+	t.Run("chunked_json", func(t *testing.T) {
+		body := `{"name":"Alice","email":"alice@example.com"}`
+		// 非 strings.Reader 的 body 不会被推断长度，ContentLength 为 -1
+		req := httptest.NewRequest(http.MethodPost, "/users", io.NopCloser(strings.NewReader(body)))
+		req.Header.Set("Content-Type", "application/json")
+		req.TransferEncoding = []string{"chunked"}
+		w := httptest.NewRecorder()
+
+		assert.Equal(t, int64(-1), req.ContentLength)
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp TestRequest
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", resp.Name)
+		assert.Equal(t, "alice@example.com", resp.Email)
+	})
+
+	t.Run("chunked_json_over_http", func(t *testing.T) {
+		server := httptest.NewServer(r)
+		defer server.Close()
+
+		body := `{"name":"Bob","email":"bob@example.com"}`
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/users", io.NopCloser(strings.NewReader(body)))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result TestRequest
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, "Bob", result.Name)
+	})
+
+	t.Run("empty_body", func(t *testing.T) {
+		r2 := gin.New()
+		r2.POST("/tasks", WrapHandler(
+			func(ctx context.Context, req TestQueryRequest) (TestQueryRequest, error) {
+				return req, nil
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodPost, "/tasks?page=3", nil)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r2.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"Page":3`)
+	})
+}
+
+// TestDefaultDecoderTextJSON tests binding a body sent as text/json
+func TestDefaultDecoderTextJSON(t *testing.T) {
+	r := gin.New()
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestRequest, error) {
+			return req, nil
+		},
+	))
+
+	body := `{"name":"Alice","email":"alice@example.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/json; charset=utf-8")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp TestRequest
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", resp.Name)
+	assert.Equal(t, "alice@example.com", resp.Email)
+}
+
+// TestDefaultDecoderFormWithQuery tests binding a urlencoded form body together with query params
+func TestDefaultDecoderFormWithQuery(t *testing.T) {
+	type FormRequest struct {
+		Name   string `form:"name"`
+		Source string `form:"source"`
+		Page   int    `form:"page"`
+	}
+
+	r := gin.New()
+	r.POST("/signup", WrapHandler(
+		func(ctx context.Context, req FormRequest) (FormRequest, error) {
+			return req, nil
+		},
+	))
+
+	t.Run("form_and_query", func(t *testing.T) {
+		body := "name=Alice"
+		req := httptest.NewRequest(http.MethodPost, "/signup?source=ads&page=2", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"Name":"Alice","Source":"ads","Page":2}`, w.Body.String())
+	})
+
+	t.Run("body_wins_on_collision", func(t *testing.T) {
+		body := "name=Alice&source=form"
+		req := httptest.NewRequest(http.MethodPost, "/signup?source=query", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"Name":"Alice","Source":"form","Page":0}`, w.Body.String())
+	})
+}
+
+// TestDefaultDecoderRawBody tests binding the raw request body into a tagged field
+func TestDefaultDecoderRawBody(t *testing.T) {
+	type WebhookRequest struct {
+		Source  string          `uri:"source"`
+		Payload []byte          `json:"-" body:"raw"`
+		Raw     json.RawMessage `json:"-" body:"raw"`
+		Event   string          `json:"event"`
+	}
+
+	var received WebhookRequest
+	r := gin.New()
+	r.POST("/webhooks/:source", WrapConsumer(
+		func(ctx context.Context, req WebhookRequest) error {
+			received = req
+			return nil
+		},
+	))
+
+	t.Run("arbitrary_bytes", func(t *testing.T) {
+		body := "\x00\x01binary payload\xff"
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/octet-stream")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "github", received.Source)
+		assert.Equal(t, []byte(body), received.Payload)
+	})
+
+	t.Run("json_still_binds", func(t *testing.T) {
+		body := `{"event":"push",  "ref":"main"}`
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "push", received.Event)
+		assert.Equal(t, []byte(body), received.Payload)
+		assert.Equal(t, json.RawMessage(body), received.Raw)
+	})
+
+	t.Run("pointer_input", func(t *testing.T) {
+		var got *WebhookRequest
+		r2 := gin.New()
+		r2.POST("/webhooks/:source", WrapConsumer(
+			func(ctx context.Context, req *WebhookRequest) error {
+				got = req
+				return nil
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", strings.NewReader("raw"))
+		req.Header.Set("Content-Type", "text/plain")
+		w := httptest.NewRecorder()
+
+		r2.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotNil(t, got)
+		assert.Equal(t, []byte("raw"), got.Payload)
+	})
 }
 
 // TestCustomDecoder tests custom decoder functionality
@@ -402,6 +800,101 @@ func TestCustomEncoder(t *testing.T) {
 	assert.Equal(t, "操作成功", resp.Message)
 }
 
+// TestOptionsAcrossWrappers tests that encoder and error handler options compose with all four wrappers
+func TestOptionsAcrossWrappers(t *testing.T) {
+	errHandler := WithErrorHandler(func(c *gin.Context, err error) {
+		c.JSON(http.StatusTeapot, gin.H{"custom": err.Error()})
+	})
+	failed := errors.New("failed")
+
+	r := gin.New()
+	r.GET("/getter", WrapGetter(
+		func(ctx context.Context) (TestResponse, error) {
+			return TestResponse{Name: "Alice"}, nil
+		},
+		WithTypedEncoder(func(c *gin.Context, output TestResponse) error {
+			c.String(http.StatusOK, "name="+output.Name)
+			return nil
+		}),
+	))
+	r.GET("/handler", WrapHandler(func(ctx context.Context, _ struct{}) (TestResponse, error) {
+		return TestResponse{}, failed
+	}, errHandler))
+	r.GET("/getter_error", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{}, failed
+	}, errHandler))
+	r.GET("/consumer", WrapConsumer(func(ctx context.Context, _ struct{}) error {
+		return failed
+	}, errHandler))
+	r.GET("/action", WrapAction(func(ctx context.Context) error {
+		return failed
+	}, errHandler))
+
+	t.Run("getter_typed_encoder", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/getter", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "name=Alice", w.Body.String())
+	})
+
+	for _, path := range []string{"/handler", "/getter_error", "/consumer", "/action"} {
+		t.Run("error_handler"+strings.ReplaceAll(path, "/", "_"), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusTeapot, w.Code)
+			assert.JSONEq(t, `{"custom":"failed"}`, w.Body.String())
+		})
+	}
+}
+
+// TestTypedEncoder tests custom encoders receiving the concrete output type
+func TestTypedEncoder(t *testing.T) {
+	r := gin.New()
+
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestResponse, error) {
+			return TestResponse{ID: 1, Name: req.Name, Email: req.Email}, nil
+		},
+		WithTypedEncoder(func(c *gin.Context, output TestResponse) error {
+			c.JSON(http.StatusOK, gin.H{"code": "SUCCESS", "name": output.Name})
+			return nil
+		}),
+	))
+	r.GET("/mismatch", WrapGetter(
+		func(ctx context.Context) (TestResponse, error) {
+			return TestResponse{}, nil
+		},
+		WithTypedEncoder(func(c *gin.Context, output string) error {
+			c.String(http.StatusOK, output)
+			return nil
+		}),
+	))
+
+	t.Run("typed", func(t *testing.T) {
+		body := `{"name":"Alice","email":"alice@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"code":"SUCCESS","name":"Alice"}`, w.Body.String())
+	})
+
+	t.Run("wrong_type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/mismatch", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), ErrEncoderReceivedWrongType.Error())
+	})
+}
+
 // TestCustomErrorHandler tests custom error handler functionality
 func TestCustomErrorHandler(t *testing.T) {
 	r := gin.New()
@@ -469,15 +962,1169 @@ func TestMergeOptions(t *testing.T) {
 	assert.NotNil(t, opts.errorHandler)
 }
 
-// BenchmarkWrapHandler benchmarks the WrapHandler function
-func BenchmarkWrapHandler(b *testing.B) {
+// TestWrapCreatedAndAccepted tests the 201/202 success status wrappers
+func TestWrapCreatedAndAccepted(t *testing.T) {
+	h := func(ctx context.Context, req TestRequest) (TestResponse, error) {
+		if req.Name == "fail" {
+			return TestResponse{}, handler.ErrConflict
+		}
+		return TestResponse{ID: 1, Name: req.Name}, nil
+	}
+
 	r := gin.New()
+	r.POST("/users", WrapCreated(h))
+	r.POST("/jobs", WrapAccepted(h))
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"created", "/users", `{"name":"Alice","email":"alice@example.com"}`, http.StatusCreated},
+		{"accepted", "/jobs", `{"name":"Alice","email":"alice@example.com"}`, http.StatusAccepted},
+		{"error_status_unchanged", "/users", `{"name":"fail","email":"alice@example.com"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
 
-	r.POST("/users", WrapHandler(
-		func(ctx context.Context, req TestRequest) (TestResponse, error) {
-			return TestResponse{
-				ID:    1,
-				Name:  req.Name,
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusConflict {
+				assert.JSONEq(t, `{"id":1,"name":"Alice","email":""}`, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestWrapCreator tests the WrapCreator functionality
+func TestWrapCreator(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		r := gin.New()
+		r.POST("/users", WrapCreator(
+			func(ctx context.Context, req TestRequest) (TestResponse, error) {
+				return TestResponse{ID: 42, Name: req.Name, Email: req.Email}, nil
+			},
+			func(resp TestResponse) string {
+				return fmt.Sprintf("/users/%d", resp.ID)
+			},
+		))
+
+		body := `{"name":"Alice","email":"alice@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/users/42", w.Header().Get("Location"))
+
+		var resp TestResponse
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), resp.ID)
+		assert.Equal(t, "Alice", resp.Name)
+	})
+
+	t.Run("handler_error", func(t *testing.T) {
+		r := gin.New()
+		r.POST("/users", WrapCreator(
+			func(ctx context.Context, req TestRequest) (TestResponse, error) {
+				return TestResponse{}, errors.New("create failed")
+			},
+			func(resp TestResponse) string {
+				return fmt.Sprintf("/users/%d", resp.ID)
+			},
+		))
+
+		body := `{"name":"Alice","email":"alice@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("Location"))
+		assert.Contains(t, w.Body.String(), "create failed")
+	})
+
+	t.Run("encoder_options", func(t *testing.T) {
+		type CreatedGroup struct {
+			ID      int64    `json:"id"`
+			Members []string `json:"members"`
+		}
+
+		marshaled := false
+		r := gin.New()
+		r.POST("/groups", WrapCreator(
+			func(ctx context.Context, req TestRequest) (CreatedGroup, error) {
+				return CreatedGroup{ID: 7}, nil
+			},
+			func(resp CreatedGroup) string {
+				return fmt.Sprintf("/groups/%d", resp.ID)
+			},
+			WithNilSliceAsEmpty(),
+			WithJSONCodec(func(v any) ([]byte, error) {
+				marshaled = true
+				return json.Marshal(v)
+			}, json.Unmarshal),
+		))
+
+		body := `{"name":"Admins","email":"admins@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/groups", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/groups/7", w.Header().Get("Location"))
+		assert.JSONEq(t, `{"id":7,"members":[]}`, w.Body.String())
+		assert.True(t, marshaled)
+	})
+}
+
+// TestWrapCacheable tests translating CacheHint into Cache-Control headers
+func TestWrapCacheable(t *testing.T) {
+	tests := []struct {
+		name   string
+		hint   CacheHint
+		header string
+	}{
+		{"public", CacheHint{MaxAge: time.Minute}, "public, max-age=60"},
+		{"private", CacheHint{MaxAge: 30 * time.Second, Private: true}, "private, max-age=30"},
+		{"no_store", CacheHint{MaxAge: time.Hour, NoStore: true}, "no-store"},
+		{"zero", CacheHint{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/users/:id", WrapCacheable(
+				func(ctx context.Context, req TestURIRequest) (TestResponse, CacheHint, error) {
+					return TestResponse{ID: req.ID}, tt.hint, nil
+				},
+			))
+
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.header, w.Header().Get("Cache-Control"))
+			assert.Contains(t, w.Body.String(), `"id":1`)
+		})
+	}
+
+	t.Run("error_skips_header", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/users/:id", WrapCacheable(
+			func(ctx context.Context, req TestURIRequest) (TestResponse, CacheHint, error) {
+				return TestResponse{}, CacheHint{MaxAge: time.Minute}, errors.New("db down")
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("Cache-Control"))
+	})
+}
+
+// failingReader returns data once and then fails with err
+type failingReader struct {
+	data   []byte
+	err    error
+	closed bool
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func (r *failingReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+// TestWrapStreamReader tests the WrapStreamReader functionality
+func TestWrapStreamReader(t *testing.T) {
+	type DownloadRequest struct {
+		Name string `uri:"name"`
+	}
+
+	t.Run("success", func(t *testing.T) {
+		reader := &failingReader{data: []byte("hello,world"), err: io.EOF}
+
+		r := gin.New()
+		r.GET("/files/:name", WrapStreamReader(
+			func(ctx context.Context, req DownloadRequest) (io.Reader, string, error) {
+				assert.Equal(t, "report.csv", req.Name)
+				return reader, "text/csv", nil
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/files/report.csv", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		assert.Equal(t, "hello,world", w.Body.String())
+		assert.True(t, reader.closed)
+	})
+
+	t.Run("handler_error", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/files/:name", WrapStreamReader(
+			func(ctx context.Context, req DownloadRequest) (io.Reader, string, error) {
+				return nil, "", errors.New("file not found")
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/files/missing", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "file not found")
+	})
+
+	t.Run("error_before_first_write", func(t *testing.T) {
+		reader := &failingReader{err: errors.New("read failed")}
+
+		r := gin.New()
+		r.GET("/files/:name", WrapStreamReader(
+			func(ctx context.Context, req DownloadRequest) (io.Reader, string, error) {
+				return reader, "text/plain", nil
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/files/broken", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "read failed")
+		assert.True(t, reader.closed)
+	})
+
+	t.Run("error_mid_stream", func(t *testing.T) {
+		reader := &failingReader{data: []byte("partial"), err: errors.New("read failed")}
+
+		r := gin.New()
+		r.GET("/files/:name", WrapStreamReader(
+			func(ctx context.Context, req DownloadRequest) (io.Reader, string, error) {
+				return reader, "text/plain", nil
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/files/broken", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "partial", w.Body.String())
+		assert.NotContains(t, w.Body.String(), "read failed")
+		assert.True(t, reader.closed)
+	})
+}
+
+// TestWrapFile tests file downloads with Content-Disposition and Content-Length
+func TestWrapFile(t *testing.T) {
+	t.Run("attachment", func(t *testing.T) {
+		reader := &failingReader{data: []byte("id,name\n1,Alice\n"), err: io.EOF}
+		r := gin.New()
+		r.GET("/reports/:id", WrapFile(func(ctx context.Context, req TestURIRequest) (FileResult, error) {
+			return FileResult{
+				Filename:    "report-" + strconv.FormatInt(req.ID, 10) + ".csv",
+				ContentType: "text/csv",
+				Reader:      reader,
+				Size:        16,
+			}, nil
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/reports/7", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=report-7.csv`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "16", w.Header().Get("Content-Length"))
+		assert.Equal(t, "id,name\n1,Alice\n", w.Body.String())
+		assert.True(t, reader.closed)
+	})
+
+	t.Run("non_ascii_filename_and_defaults", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/export", WrapFile(func(ctx context.Context, _ struct{}) (FileResult, error) {
+			return FileResult{Filename: "报表.xlsx", Reader: strings.NewReader("data")}, nil
+		}))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename*=utf-8''%E6%8A%A5%E8%A1%A8.xlsx`, w.Header().Get("Content-Disposition"))
+		// strings.Reader 可 Seek，Content-Length 由内容长度得到
+		assert.Equal(t, "4", w.Header().Get("Content-Length"))
+		assert.Equal(t, "data", w.Body.String())
+	})
+
+	t.Run("range_on_seekable_reader", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/video", WrapFile(func(ctx context.Context, _ struct{}) (FileResult, error) {
+			return FileResult{Filename: "video.mp4", ContentType: "video/mp4", Reader: strings.NewReader("0123456789")}, nil
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/video", nil)
+		req.Header.Set("Range", "bytes=2-5")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPartialContent, w.Code)
+		assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+		assert.Equal(t, "bytes 2-5/10", w.Header().Get("Content-Range"))
+		assert.Equal(t, "4", w.Header().Get("Content-Length"))
+		assert.Equal(t, "video/mp4", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=video.mp4`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "2345", w.Body.String())
+
+		// 不带 Range 时返回完整内容
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/video", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+		assert.Equal(t, "0123456789", w.Body.String())
+
+		// 无法满足的 Range 返回 416
+		req = httptest.NewRequest(http.MethodGet, "/video", nil)
+		req.Header.Set("Range", "bytes=20-30")
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+	})
+
+	t.Run("range_on_non_seekable_reader", func(t *testing.T) {
+		reader := &failingReader{data: []byte("0123456789"), err: io.EOF}
+		r := gin.New()
+		r.GET("/stream", WrapFile(func(ctx context.Context, _ struct{}) (FileResult, error) {
+			return FileResult{Filename: "stream.bin", Reader: reader}, nil
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+		req.Header.Set("Range", "bytes=2-5")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Accept-Ranges"))
+		assert.Empty(t, w.Header().Get("Content-Range"))
+		assert.Equal(t, "0123456789", w.Body.String())
+		assert.True(t, reader.closed)
+	})
+
+	t.Run("error_before_streaming", func(t *testing.T) {
+		reader := &failingReader{err: errors.New("read failed")}
+		r := gin.New()
+		r.GET("/broken", WrapFile(func(ctx context.Context, _ struct{}) (FileResult, error) {
+			return FileResult{Filename: "broken.bin", Reader: reader, Size: 100}, nil
+		}))
+		r.GET("/missing", WrapFile(func(ctx context.Context, _ struct{}) (FileResult, error) {
+			return FileResult{}, handler.ErrNotFound
+		}))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/broken", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+		assert.NotEqual(t, "100", w.Header().Get("Content-Length"))
+		assert.True(t, reader.closed)
+
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+	})
+}
+
+// TestDefaultErrorHandlerSentinels tests mapping standard errors to status codes
+func TestDefaultErrorHandlerSentinels(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"bad_request", handler.ErrBadRequest, http.StatusBadRequest},
+		{"unauthorized", handler.ErrUnauthorized, http.StatusUnauthorized},
+		{"forbidden", handler.ErrForbidden, http.StatusForbidden},
+		{"not_found", fmt.Errorf("user %w", handler.ErrNotFound), http.StatusNotFound},
+		{"conflict", fmt.Errorf("email taken: %w", handler.ErrConflict), http.StatusConflict},
+		{"gone", fmt.Errorf("user removed: %w", handler.ErrGone), http.StatusGone},
+		{"unknown", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/users/:id", WrapHandler(
+				func(ctx context.Context, req TestURIRequest) (TestResponse, error) {
+					return TestResponse{}, tt.err
+				},
+			))
+
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.err.Error())
+		})
+	}
+}
+
+// TestDefaultErrorHandlerWithStatus tests overriding the status of the default error handler
+func TestDefaultErrorHandlerWithStatus(t *testing.T) {
+	errTooLarge := errors.New("payload too large")
+
+	statusFor := func(err error) int {
+		if errors.Is(err, errTooLarge) {
+			return http.StatusRequestEntityTooLarge
+		}
+		return 0
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"custom_status", errTooLarge, http.StatusRequestEntityTooLarge},
+		{"fallback_500", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.POST("/upload", WrapAction(
+				func(ctx context.Context) error {
+					return tt.err
+				},
+				WithErrorHandler(DefaultErrorHandlerWithStatus(statusFor)),
+			))
+
+			req := httptest.NewRequest(http.MethodPost, "/upload", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.JSONEq(t, fmt.Sprintf(`{"error":%q}`, tt.err.Error()), w.Body.String())
+		})
+	}
+}
+
+// detailedError 带有详细信息的测试错误
+type detailedError struct {
+	details []handler.ErrorDetail
+}
+
+func (e *detailedError) Error() string {
+	return "invalid user"
+}
+
+func (e *detailedError) Details() []handler.ErrorDetail {
+	return e.details
+}
+
+// TestDefaultErrorHandlerDetails tests including error details in the default error payload
+func TestDefaultErrorHandlerDetails(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		body   string
+	}{
+		{
+			name: "with_details",
+			err: fmt.Errorf("create user: %w", &detailedError{details: []handler.ErrorDetail{
+				{Field: "email", Issue: "already taken"},
+			}}),
+			status: http.StatusInternalServerError,
+			body:   `{"error":{"message":"create user: invalid user","details":[{"field":"email","issue":"already taken"}]}}`,
+		},
+		{
+			name:   "empty_details",
+			err:    &detailedError{},
+			status: http.StatusInternalServerError,
+			body:   `{"error":"invalid user"}`,
+		},
+		{
+			name:   "path_param",
+			err:    &PathParamError{Param: "id", Value: "abc", Err: errors.New("invalid syntax")},
+			status: http.StatusBadRequest,
+			body:   `{"error":{"message":"invalid path parameter \"id\": \"abc\": invalid syntax","details":[{"field":"id","issue":"invalid value \"abc\""}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.POST("/users", WrapAction(func(ctx context.Context) error {
+				return tt.err
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/users", nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.JSONEq(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+// TestValidationErrorHandler tests returning validation errors as a JSON field map
+func TestValidationErrorHandler(t *testing.T) {
+	type address struct {
+		City string `json:"city" binding:"required"`
+	}
+	type signupRequest struct {
+		Email    string   `json:"email" binding:"required,email"`
+		Age      int      `json:"age" binding:"gte=18"`
+		Nickname string   `binding:"max=3"`
+		Address  address  `json:"address"`
+		Tags     []string `json:"tags" binding:"dive,oneof=a b"`
+	}
+
+	r := gin.New()
+	r.POST("/signup", WrapHandler(func(ctx context.Context, req signupRequest) (TestResponse, error) {
+		if req.Email == "taken@example.com" {
+			return TestResponse{}, fmt.Errorf("email: %w", handler.ErrConflict)
+		}
+		return TestResponse{Email: req.Email}, nil
+	}, WithErrorHandler(ValidationErrorHandler())))
+
+	do := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("field_map", func(t *testing.T) {
+		w := do(`{"email":"not-an-email","age":17,"Nickname":"toolong","tags":["a","c"]}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{"errors":{
+			"email":"must be a valid email",
+			"age":"must be greater than or equal to 18",
+			"Nickname":"must be at most 3",
+			"address.city":"is required",
+			"tags[1]":"must be one of a b"
+		}}`, w.Body.String())
+	})
+
+	t.Run("non_validation_error", func(t *testing.T) {
+		w := do(`{"email":"taken@example.com","age":20,"address":{"city":"Paris"}}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"error":"email: conflict"}`, w.Body.String())
+	})
+}
+
+// TestWithInputPool tests reusing pooled input structs between requests
+func TestWithInputPool(t *testing.T) {
+	type poolRequest struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+
+	r := gin.New()
+	var seen []poolRequest
+	r.POST("/items", WrapHandler(
+		func(ctx context.Context, req poolRequest) (poolRequest, error) {
+			seen = append(seen, req)
+			return req, nil
+		},
+		WithInputPool(),
+	))
+
+	for _, body := range []string{`{"name":"first","tags":["a","b"]}`, `{"name":"second"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// 第二次请求不应残留第一次请求的字段
+	assert.Equal(t, []poolRequest{
+		{Name: "first", Tags: []string{"a", "b"}},
+		{Name: "second"},
+	}, seen)
+}
+
+// TestWithInputPoolAllocations tests that pooled inputs avoid the per-request allocation of a copy of the input
+func TestWithInputPoolAllocations(t *testing.T) {
+	type largeRequest struct {
+		Name    string    `json:"name"`
+		Payload [600]byte `json:"-"`
+	}
+
+	newRouter := func(options ...WrapHandlerOptionFunc) *gin.Engine {
+		r := gin.New()
+		r.POST("/items", WrapHandler(func(ctx context.Context, req largeRequest) (struct{}, error) {
+			return struct{}{}, nil
+		}, append(options, WithEncoder(func(c *gin.Context, output any) error {
+			c.Status(http.StatusNoContent)
+			return nil
+		}))...))
+		return r
+	}
+	allocs := func(r *gin.Engine) float64 {
+		body := strings.NewReader("")
+		req := httptest.NewRequest(http.MethodPost, "/items", body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		return testing.AllocsPerRun(100, func() {
+			body.Reset(`{"name":"alice"}`)
+			req.ContentLength = int64(body.Len())
+			r.ServeHTTP(w, req)
+		})
+	}
+
+	defaultAllocs := allocs(newRouter())
+	pooledAllocs := allocs(newRouter(WithInputPool()))
+	t.Logf("allocs/op: default=%v pooled=%v", defaultAllocs, pooledAllocs)
+	assert.Less(t, pooledAllocs, defaultAllocs)
+}
+
+// TestWithSingleflight tests that concurrent identical requests share one handler execution
+func TestWithSingleflight(t *testing.T) {
+	type ReportRequest struct {
+		Month string `form:"month"`
+	}
+
+	var calls atomic.Int32
+	var arrived atomic.Int32
+	release := make(chan struct{})
+	var keys sync.Map
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		arrived.Add(1)
+		c.Next()
+	})
+	h := func(ctx context.Context, req *ReportRequest) (TestResponse, error) {
+		calls.Add(1)
+		<-release
+		if req.Month == "bad" {
+			return TestResponse{}, errors.New("report failed")
+		}
+		return TestResponse{Name: "report-" + req.Month}, nil
+	}
+	r.GET("/reports", WrapHandler(h, WithSingleflight(func(input any) string {
+		// 指针输入解引用后传给 keyFn
+		req := input.(ReportRequest)
+		keys.Store(req.Month, true)
+		return req.Month
+	})))
+	r.GET("/default-key", WrapHandler(h, WithSingleflight(nil)))
+	r.GET("/cacheable", WrapCacheable(func(ctx context.Context, req ReportRequest) (TestResponse, CacheHint, error) {
+		resp, err := h(ctx, &req)
+		return resp, CacheHint{MaxAge: time.Minute}, err
+	}, WithSingleflight(nil)))
+	r.GET("/ctx-header", WrapHandlerCtx(func(c *gin.Context, ctx context.Context, req ReportRequest) (TestResponse, error) {
+		c.Header("X-Report-Version", "3")
+		return h(ctx, &req)
+	}, WithSingleflight(nil)))
+
+	run := func(path string, n int) []*httptest.ResponseRecorder {
+		calls.Store(0)
+		arrived.Store(0)
+		release = make(chan struct{})
+
+		recorders := make([]*httptest.ResponseRecorder, n)
+		var wg sync.WaitGroup
+		for i := range recorders {
+			recorders[i] = httptest.NewRecorder()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.ServeHTTP(recorders[i], httptest.NewRequest(http.MethodGet, path, nil))
+			}()
+		}
+		assert.Eventually(t, func() bool { return arrived.Load() == int32(n) }, time.Second, time.Millisecond)
+		// 等待所有请求进入合并组后再放行
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		return recorders
+	}
+
+	t.Run("shared_result", func(t *testing.T) {
+		for _, w := range run("/reports?month=2024-01", 5) {
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), "report-2024-01")
+		}
+		assert.Equal(t, int32(1), calls.Load())
+		_, ok := keys.Load("2024-01")
+		assert.True(t, ok)
+	})
+
+	t.Run("shared_error", func(t *testing.T) {
+		for _, w := range run("/reports?month=bad", 3) {
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.Contains(t, w.Body.String(), "report failed")
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("default_key", func(t *testing.T) {
+		for _, w := range run("/default-key?month=2024-02", 4) {
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("shared_headers", func(t *testing.T) {
+		for _, w := range run("/cacheable?month=2024-03", 3) {
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+		}
+		assert.Equal(t, int32(1), calls.Load())
+
+		for _, w := range run("/ctx-header?month=2024-03", 3) {
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "3", w.Header().Get("X-Report-Version"))
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("different_keys", func(t *testing.T) {
+		// release 已在上一次 run 中关闭，处理器不再阻塞
+		calls.Store(0)
+		for _, month := range []string{"a", "b"} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reports?month="+month, nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+// TestWithErrorHandlerEx tests that the error handler receives the decoded input
+func TestWithErrorHandlerEx(t *testing.T) {
+	var gotInput any
+	r := gin.New()
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestResponse, error) {
+			return TestResponse{}, errors.New("insert failed")
+		},
+		WithErrorHandlerEx(func(c *gin.Context, input any, err error) {
+			gotInput = input
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}),
+	))
+
+	t.Run("handler_error", func(t *testing.T) {
+		body := `{"name":"Alice","email":"alice@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "insert failed")
+		assert.Equal(t, TestRequest{Name: "Alice", Email: "alice@example.com"}, gotInput)
+	})
+
+	t.Run("decode_error", func(t *testing.T) {
+		gotInput = "unset"
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Nil(t, gotInput)
+	})
+}
+
+// TestWithErrorObserver tests that observers see every error before the error handler runs
+func TestWithErrorObserver(t *testing.T) {
+	var events []string
+	observe := func(name string) ErrorObserverFunc {
+		return func(c *gin.Context, err error) {
+			events = append(events, name+":"+err.Error())
+		}
+	}
+
+	r := gin.New()
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestResponse, error) {
+			return TestResponse{}, errors.New("handle failed")
+		},
+		WithErrorObserver(observe("first")),
+		WithErrorHandler(func(c *gin.Context, err error) {
+			events = append(events, "handler:"+err.Error())
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}),
+		WithErrorObserver(observe("second")),
+	))
+	r.GET("/encode", WrapGetter(
+		func(ctx context.Context) (TestResponse, error) {
+			return TestResponse{}, nil
+		},
+		WithEncoder(func(c *gin.Context, output any) error {
+			return errors.New("encode failed")
+		}),
+		WithErrorObserver(observe("observer")),
+	))
+
+	t.Run("handle_error", func(t *testing.T) {
+		events = nil
+		body := `{"name":"Alice","email":"alice@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []string{"first:handle failed", "second:handle failed", "handler:handle failed"}, events)
+	})
+
+	t.Run("decode_error", func(t *testing.T) {
+		events = nil
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{invalid`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Len(t, events, 3)
+	})
+
+	t.Run("encode_error_keeps_default_handler", func(t *testing.T) {
+		events = nil
+		req := httptest.NewRequest(http.MethodGet, "/encode", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"encode failed"}`, w.Body.String())
+		assert.Equal(t, []string{"observer:encode failed"}, events)
+	})
+}
+
+// TestPartialSuccess tests bulk handlers returning per-item results and errors
+func TestPartialSuccess(t *testing.T) {
+	type bulkItem struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+
+	r := gin.New()
+	r.POST("/users/bulk", WrapHandler(
+		func(ctx context.Context, req []bulkItem) (handler.PartialSuccess[TestResponse], error) {
+			var result handler.PartialSuccess[TestResponse]
+			for i, item := range req {
+				if item.Email == "" {
+					result.Fail(i, fmt.Errorf("email is required: %w", handler.ErrBadRequest))
+					continue
+				}
+				result.Succeed(TestResponse{ID: int64(i + 1), Name: item.Name, Email: item.Email})
+			}
+			return result, nil
+		},
+	))
+
+	t.Run("mixed", func(t *testing.T) {
+		body := `[{"name":"Alice","email":"alice@example.com"},{"name":"Bob"},{"name":"Carol","email":"carol@example.com"}]`
+		req := httptest.NewRequest(http.MethodPost, "/users/bulk", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"results": [
+				{"id":1,"name":"Alice","email":"alice@example.com"},
+				{"id":3,"name":"Carol","email":"carol@example.com"}
+			],
+			"errors": [
+				{"index":1,"error":"email is required: bad request"}
+			]
+		}`, w.Body.String())
+	})
+
+	t.Run("all_succeed", func(t *testing.T) {
+		body := `[{"name":"Alice","email":"alice@example.com"}]`
+		req := httptest.NewRequest(http.MethodPost, "/users/bulk", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"results":[{"id":1,"name":"Alice","email":"alice@example.com"}],"errors":[]}`, w.Body.String())
+	})
+}
+
+// TestWithResponseTransformer tests transforming the output before encoding
+func TestWithResponseTransformer(t *testing.T) {
+	type roleKey struct{}
+
+	// 非管理员隐藏邮箱字段
+	redactEmail := func(ctx context.Context, output any) (any, error) {
+		resp, ok := output.(TestResponse)
+		if !ok {
+			return nil, ErrEncoderReceivedWrongType
+		}
+		if ctx.Value(roleKey{}) != "admin" {
+			resp.Email = ""
+		}
+		return resp, nil
+	}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		ctx := context.WithValue(c.Request.Context(), roleKey{}, c.GetHeader("X-Role"))
+		c.Request = c.Request.WithContext(ctx)
+	})
+	r.GET("/users/:id", WrapHandler(
+		func(ctx context.Context, req TestURIRequest) (TestResponse, error) {
+			return TestResponse{ID: req.ID, Name: "Alice", Email: "alice@example.com"}, nil
+		},
+		WithResponseTransformer(redactEmail),
+		WithResponseTransformer(func(ctx context.Context, output any) (any, error) {
+			if output.(TestResponse).ID == 404 {
+				return nil, handler.ErrNotFound
+			}
+			return gin.H{"user": output}, nil
+		}),
+	))
+
+	t.Run("admin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set("X-Role", "admin")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user":{"id":1,"name":"Alice","email":"alice@example.com"}}`, w.Body.String())
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user":{"id":1,"name":"Alice","email":""}}`, w.Body.String())
+	})
+
+	t.Run("transformer_error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/404", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// TestSliceAndMapInput tests binding top-level slice and map request bodies
+func TestSliceAndMapInput(t *testing.T) {
+	r := gin.New()
+	r.POST("/orgs/:org/users", WrapHandler(
+		func(ctx context.Context, req []TestRequest) ([]TestResponse, error) {
+			resp := make([]TestResponse, len(req))
+			for i, item := range req {
+				resp[i] = TestResponse{ID: int64(i + 1), Name: item.Name, Email: item.Email}
+			}
+			return resp, nil
+		},
+	))
+	r.PUT("/orgs/:org/labels", WrapHandler(
+		func(ctx context.Context, req map[string]string) (map[string]string, error) {
+			return req, nil
+		},
+	))
+
+	t.Run("slice", func(t *testing.T) {
+		body := `[{"name":"Alice","email":"alice@example.com"},{"name":"Bob","email":"bob@example.com"}]`
+		req := httptest.NewRequest(http.MethodPost, "/orgs/acme/users?dry_run=true", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[
+			{"id":1,"name":"Alice","email":"alice@example.com"},
+			{"id":2,"name":"Bob","email":"bob@example.com"}
+		]`, w.Body.String())
+	})
+
+	t.Run("slice_element_validation", func(t *testing.T) {
+		body := `[{"name":"Alice"}]`
+		req := httptest.NewRequest(http.MethodPost, "/orgs/acme/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "Email")
+	})
+
+	t.Run("map", func(t *testing.T) {
+		body := `{"env":"prod","team":"core"}`
+		req := httptest.NewRequest(http.MethodPut, "/orgs/acme/labels?force=1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, body, w.Body.String())
+	})
+}
+
+// TestWithOptionalBody tests endpoints whose request body may be omitted
+func TestWithOptionalBody(t *testing.T) {
+	type optionsRequest struct {
+		Force   bool   `json:"force"`
+		Comment string `json:"comment"`
+	}
+	type updateRequest struct {
+		ID   int64  `uri:"id" binding:"required"`
+		Name string `json:"name" binding:"required"`
+	}
+
+	var seen optionsRequest
+	var updated updateRequest
+	r := gin.New()
+	r.POST("/jobs", WrapConsumer(func(ctx context.Context, req optionsRequest) error {
+		seen = req
+		return nil
+	}))
+	r.PUT("/users/:id", WrapConsumer(
+		func(ctx context.Context, req updateRequest) error {
+			updated = req
+			return nil
+		},
+		WithOptionalBody(),
+	))
+	r.PATCH("/users/:id", WrapConsumer(func(ctx context.Context, req updateRequest) error {
+		return nil
+	}))
+
+	t.Run("no_body_without_required_fields", func(t *testing.T) {
+		seen = optionsRequest{Force: true}
+		req := httptest.NewRequest(http.MethodPost, "/jobs", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, optionsRequest{}, seen)
+	})
+
+	t.Run("empty_chunked_body", func(t *testing.T) {
+		seen = optionsRequest{Force: true}
+		req := httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(""))
+		req.Header.Set("Content-Type", "application/json")
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, optionsRequest{}, seen)
+	})
+
+	t.Run("optional_body_skips_required", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/users/7", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, updateRequest{ID: 7}, updated)
+	})
+
+	t.Run("optional_body_present_is_validated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/users/7", strings.NewReader(`{"comment":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "Name")
+	})
+
+	t.Run("required_body_without_option", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/users/7", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "Name")
+	})
+}
+
+// BenchmarkWrapHandler benchmarks the WrapHandler function
+func BenchmarkWrapHandler(b *testing.B) {
+	r := gin.New()
+
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestResponse, error) {
+			return TestResponse{
+				ID:    1,
+				Name:  req.Name,
 				Email: req.Email,
 			}, nil
 		},
@@ -494,3 +2141,261 @@ func BenchmarkWrapHandler(b *testing.B) {
 		r.ServeHTTP(w, req)
 	}
 }
+
+// TestWithSlowLog tests that only requests exceeding the threshold are logged
+func TestWithSlowLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	r := gin.New()
+	r.GET("/reports/:id", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		time.Sleep(20 * time.Millisecond)
+		return TestResponse{Name: "slow"}, nil
+	}, WithSlowLog(10*time.Millisecond, logger)))
+	r.GET("/fast", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{Name: "fast"}, nil
+	}, WithSlowLog(time.Second, logger)))
+	r.GET("/nil", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{Name: "nil"}, nil
+	}, WithSlowLog(0, nil)))
+
+	t.Run("slow", func(t *testing.T) {
+		buf.Reset()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/reports/7", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"slow"`)
+
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "WARN", record["level"])
+		assert.Equal(t, "slow request", record["msg"])
+		assert.Equal(t, "GET /reports/:id", record["handler"])
+		assert.Equal(t, "/reports/7", record["path"])
+		assert.GreaterOrEqual(t, record["duration"], float64(20*time.Millisecond))
+	})
+
+	t.Run("fast", func(t *testing.T) {
+		buf.Reset()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("nil_logger", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/nil", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+type baseCtxKey struct{}
+
+type requestCtxKey struct{}
+
+// TestWithBaseContext tests that base context values are visible while cancellation follows the request
+func TestWithBaseContext(t *testing.T) {
+	base, cancelBase := context.WithCancel(context.WithValue(context.Background(), baseCtxKey{}, "db-pool"))
+	cancelBase()
+
+	var value any
+	var baseErr, reqErr error
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestCtxKey{}, "request"))
+		c.Next()
+	})
+	r.GET("/items", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		value = ctx.Value(baseCtxKey{})
+		baseErr = ctx.Err()
+		return TestResponse{Name: ctx.Value(requestCtxKey{}).(string)}, nil
+	}, WithBaseContext(base)))
+
+	t.Run("values_merged", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "db-pool", value)
+		assert.Contains(t, w.Body.String(), `"name":"request"`)
+		assert.NoError(t, baseErr, "base context cancellation must not reach the handler")
+	})
+
+	t.Run("request_cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		r2 := gin.New()
+		r2.GET("/items", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+			value = ctx.Value(baseCtxKey{})
+			reqErr = ctx.Err()
+			return TestResponse{}, nil
+		}, WithBaseContext(base)))
+
+		req := httptest.NewRequest(http.MethodGet, "/items", nil).WithContext(ctx)
+		r2.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "db-pool", value)
+		assert.ErrorIs(t, reqErr, context.Canceled)
+	})
+}
+
+// TestBindErrorClassification tests that malformed bodies return 400 and failed validation returns 422
+func TestBindErrorClassification(t *testing.T) {
+	var handled error
+	r := gin.New()
+	r.POST("/users", WrapHandler(func(ctx context.Context, req TestRequest) (TestResponse, error) {
+		return TestResponse{Name: req.Name}, nil
+	}, WithErrorObserver(func(c *gin.Context, err error) {
+		handled = err
+	})))
+
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		sentinel error
+	}{
+		{name: "syntax_error", body: `{"name":`, status: http.StatusBadRequest, sentinel: ErrMalformedRequest},
+		{name: "invalid_json", body: `{"name":"Alice",}`, status: http.StatusBadRequest, sentinel: ErrMalformedRequest},
+		{name: "type_mismatch", body: `{"name":123,"email":"a@example.com"}`, status: http.StatusBadRequest, sentinel: ErrMalformedRequest},
+		{name: "validation_failed", body: `{"name":"Alice","email":"not-an-email"}`, status: http.StatusUnprocessableEntity, sentinel: ErrValidationFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled = nil
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.ErrorIs(t, handled, tt.sentinel)
+		})
+	}
+
+	t.Run("validation_errors_unwrap", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Alice"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		var verrs validator.ValidationErrors
+		assert.True(t, errors.As(handled, &verrs))
+	})
+}
+
+// TestRawBodyInput tests that []byte and io.Reader inputs receive the untouched request body
+func TestRawBodyInput(t *testing.T) {
+	payload := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+	r := gin.New()
+	r.POST("/bytes", WrapHandler(func(ctx context.Context, body []byte) (TestResponse, error) {
+		return TestResponse{ID: int64(len(body)), Name: hex.EncodeToString(body)}, nil
+	}))
+	r.POST("/reader", WrapHandler(func(ctx context.Context, body io.Reader) (TestResponse, error) {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return TestResponse{}, err
+		}
+		return TestResponse{ID: int64(len(data)), Name: string(data)}, nil
+	}))
+	r.POST("/explicit", WrapHandler(func(ctx context.Context, body []byte) (TestResponse, error) {
+		return TestResponse{Name: string(body)}, nil
+	}, WithDecoder(RawBodyDecoder[[]byte]()), WithHMACVerification([]byte("secret"), "X-Signature")))
+
+	t.Run("bytes", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/bytes", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "image/png")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"`+hex.EncodeToString(payload)+`"`)
+	})
+
+	t.Run("json_not_parsed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/bytes", strings.NewReader(`"aGVsbG8="`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":10`)
+	})
+
+	t.Run("reader", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/reader", strings.NewReader("raw stream"))
+		req.Header.Set("Content-Type", "application/octet-stream")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"raw stream"`)
+	})
+
+	t.Run("empty_body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/reader", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":0`)
+	})
+
+	t.Run("with_hmac_verification", func(t *testing.T) {
+		body := `{"event":"push"}`
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest(http.MethodPost, "/explicit", strings.NewReader(body))
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"{\"event\":\"push\"}"`)
+	})
+}
+
+// TestWithDecodedInputInContext tests that the decoded input is available to error handlers only after a successful decode
+func TestWithDecodedInputInContext(t *testing.T) {
+	var input any
+	var found bool
+	r := gin.New()
+	r.POST("/users", WrapHandler(func(ctx context.Context, req TestRequest) (TestResponse, error) {
+		return TestResponse{}, handler.ErrConflict
+	}, WithDecodedInputInContext(), WithErrorHandler(func(c *gin.Context, err error) {
+		input, found = DecodedInputFromContext(c)
+		DefaultErrorHandler()(c, err)
+	})))
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("handler_error", func(t *testing.T) {
+		w := serve(`{"name":"Alice","email":"alice@example.com"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.True(t, found)
+		assert.Equal(t, TestRequest{Name: "Alice", Email: "alice@example.com"}, input)
+	})
+
+	t.Run("decode_error", func(t *testing.T) {
+		w := serve(`{"name":"Alice"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.False(t, found)
+		assert.Nil(t, input)
+	})
+}
@@ -0,0 +1,82 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 健康状态
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// HealthCheck 单个组件的健康检查
+type HealthCheck struct {
+	Name  string                          // 组件名称，作为 HealthResponse.Components 的键
+	Check func(ctx context.Context) error // 返回 nil 表示组件健康
+}
+
+// ComponentHealth 单个组件的检查结果
+type ComponentHealth struct {
+	Status string `json:"status"`          // up 或 down
+	Error  string `json:"error,omitempty"` // 检查失败时的错误信息
+}
+
+// HealthResponse 健康检查响应
+type HealthResponse struct {
+	Status     string                     `json:"status"` // 所有组件健康时为 up，否则为 down
+	Timestamp  time.Time                  `json:"timestamp"`
+	Components map[string]ComponentHealth `json:"components,omitempty"`
+}
+
+// WrapHealth 包装健康检查处理器
+// 并发执行所有检查，全部通过时返回 200，任一失败时返回 503，响应体中包含各组件的检查结果
+// 检查使用请求的 ctx，可通过中间件或客户端的超时控制检查耗时
+// 适用场景：/health、/ready 等存活和就绪探针
+func WrapHealth(checks ...HealthCheck) gin.HandlerFunc {
+	return WrapGetter(func(ctx context.Context) (HealthResponse, error) {
+		return runHealthChecks(ctx, checks), nil
+	}, WithTypedEncoder(func(c *gin.Context, resp HealthResponse) error {
+		status := http.StatusOK
+		if resp.Status != HealthStatusUp {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, resp)
+		return nil
+	}))
+}
+
+// runHealthChecks 并发执行检查并汇总结果
+func runHealthChecks(ctx context.Context, checks []HealthCheck) HealthResponse {
+	results := make([]ComponentHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := check.Check(ctx); err != nil {
+				results[i] = ComponentHealth{Status: HealthStatusDown, Error: err.Error()}
+				return
+			}
+			results[i] = ComponentHealth{Status: HealthStatusUp}
+		}()
+	}
+	wg.Wait()
+
+	resp := HealthResponse{Status: HealthStatusUp, Timestamp: time.Now()}
+	if len(checks) > 0 {
+		resp.Components = make(map[string]ComponentHealth, len(checks))
+	}
+	for i, check := range checks {
+		resp.Components[check.Name] = results[i]
+		if results[i].Status != HealthStatusUp {
+			resp.Status = HealthStatusDown
+		}
+	}
+	return resp
+}
@@ -0,0 +1,90 @@
+package ginserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrapHealth tests aggregating concurrent health checks
+func TestWrapHealth(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+
+	t.Run("all_up", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/health", WrapHealth(
+			HealthCheck{Name: "db", Check: ok},
+			HealthCheck{Name: "cache", Check: ok},
+		))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp HealthResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, HealthStatusUp, resp.Status)
+		assert.Equal(t, map[string]ComponentHealth{
+			"db":    {Status: HealthStatusUp},
+			"cache": {Status: HealthStatusUp},
+		}, resp.Components)
+	})
+
+	t.Run("one_down", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/health", WrapHealth(
+			HealthCheck{Name: "db", Check: ok},
+			HealthCheck{Name: "queue", Check: func(ctx context.Context) error {
+				return errors.New("connection refused")
+			}},
+		))
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		var resp HealthResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, HealthStatusDown, resp.Status)
+		assert.Equal(t, ComponentHealth{Status: HealthStatusDown, Error: "connection refused"}, resp.Components["queue"])
+		assert.Equal(t, HealthStatusUp, resp.Components["db"].Status)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		slow := func(ctx context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		}
+		r := gin.New()
+		r.GET("/health", WrapHealth(
+			HealthCheck{Name: "a", Check: slow},
+			HealthCheck{Name: "b", Check: slow},
+			HealthCheck{Name: "c", Check: slow},
+		))
+
+		start := time.Now()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Less(t, time.Since(start), 250*time.Millisecond)
+	})
+
+	t.Run("no_checks", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/health", WrapHealth())
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"up"`)
+	})
+}
@@ -0,0 +1,50 @@
+package ginserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrInvalidSignature 请求签名校验失败，默认错误处理器返回 401
+var ErrInvalidSignature = errors.New("invalid request signature")
+
+// hmacConfig HMAC 签名校验配置
+type hmacConfig struct {
+	secret []byte
+	header string
+}
+
+// WithHMACVerification 校验请求体的 HMAC-SHA256 签名
+// 签名从 header 指定的请求头读取，为原始请求体的十六进制摘要，可带 "sha256=" 前缀（兼容 GitHub 等 Webhook）
+// 校验时会缓存并还原请求体，校验通过后仍按常规方式绑定到输入结构体
+func WithHMACVerification(secret []byte, header string) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.hmac = &hmacConfig{secret: secret, header: header}
+	}
+}
+
+// verify 校验请求签名，失败时返回 ErrInvalidSignature
+func (cfg *hmacConfig) verify(c *gin.Context) error {
+	signature := strings.TrimPrefix(c.GetHeader(cfg.header), "sha256=")
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return ErrInvalidSignature
+	}
+
+	body, err := peekRequestBody(c)
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(sha256.New, cfg.secret)
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package ginserver
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithHMACVerification tests verifying a signed body and binding it in the same request
+func TestWithHMACVerification(t *testing.T) {
+	secret := []byte("webhook-secret")
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	var received TestRequest
+	r := gin.New()
+	r.POST("/webhooks", WrapConsumer(
+		func(ctx context.Context, req TestRequest) error {
+			received = req
+			return nil
+		},
+		WithHMACVerification(secret, "X-Signature"),
+	))
+
+	body := `{"name":"Alice","email":"alice@example.com"}`
+
+	t.Run("valid_signature_binds_body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Signature", sign(body))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Alice", received.Name)
+		assert.Equal(t, "alice@example.com", received.Email)
+	})
+
+	t.Run("invalid_signature", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Signature", sign(`{"name":"Mallory"}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), ErrInvalidSignature.Error())
+	})
+
+	t.Run("missing_signature", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
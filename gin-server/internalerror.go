@@ -0,0 +1,27 @@
+package ginserver
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// hideInternalErrors 为 true 时默认错误处理器不向客户端暴露 5xx 错误的原始信息
+// 零值表示暴露，与未调用 SetExposeInternalErrors 时的行为一致
+var hideInternalErrors atomic.Bool
+
+// SetExposeInternalErrors 设置默认错误处理器是否向客户端暴露 5xx 错误的原始信息，默认暴露
+// 关闭后 5xx 响应体中的 error 替换为状态码对应的通用描述（如 "internal server error"），
+// 原始错误仍会传给 WithErrorObserver、WithAccessLog 等观察者用于记录
+// 适用场景：生产环境关闭、开发环境开启，避免将内部实现细节泄露给客户端
+func SetExposeInternalErrors(expose bool) {
+	hideInternalErrors.Store(!expose)
+}
+
+// internalErrorPayload 关闭暴露时返回 5xx 错误的通用描述，否则返回 false
+func internalErrorPayload(status int) (string, bool) {
+	if status < http.StatusInternalServerError || !hideInternalErrors.Load() {
+		return "", false
+	}
+	return strings.ToLower(http.StatusText(status)), true
+}
@@ -0,0 +1,64 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// TestSetExposeInternalErrors tests hiding 5xx error details while observers still see the real error
+func TestSetExposeInternalErrors(t *testing.T) {
+	t.Cleanup(func() { SetExposeInternalErrors(true) })
+
+	var observed error
+	r := gin.New()
+	r.GET("/internal", WrapAction(func(ctx context.Context) error {
+		return errors.New("pq: connection to 10.0.0.5 refused")
+	}, WithErrorObserver(func(c *gin.Context, err error) {
+		observed = err
+	})))
+	r.GET("/not-found", WrapAction(func(ctx context.Context) error {
+		return handler.ErrNotFound
+	}))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("dev_mode", func(t *testing.T) {
+		SetExposeInternalErrors(true)
+
+		w := serve("/internal")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"pq: connection to 10.0.0.5 refused"}`, w.Body.String())
+	})
+
+	t.Run("prod_mode", func(t *testing.T) {
+		SetExposeInternalErrors(false)
+		observed = nil
+
+		w := serve("/internal")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"internal server error"}`, w.Body.String())
+		assert.EqualError(t, observed, "pq: connection to 10.0.0.5 refused")
+	})
+
+	t.Run("prod_mode_keeps_client_errors", func(t *testing.T) {
+		SetExposeInternalErrors(false)
+
+		w := serve("/not-found")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), handler.ErrNotFound.Error())
+	})
+}
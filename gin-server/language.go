@@ -0,0 +1,80 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// languagesCtxKey 语言偏好在 context 中的 key
+type languagesCtxKey struct{}
+
+// WithAcceptLanguage 解析 Accept-Language 请求头，并将语言偏好注入处理器的 ctx
+// 处理器通过 LanguagesFromContext 获取按优先级从高到低排列的语言列表
+func WithAcceptLanguage() WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.acceptLanguage = true
+	}
+}
+
+// LanguagesFromContext 返回 WithAcceptLanguage 注入的语言偏好，按优先级从高到低排列
+// 未启用该选项或请求未携带 Accept-Language 时返回 nil
+func LanguagesFromContext(ctx context.Context) []string {
+	languages, _ := ctx.Value(languagesCtxKey{}).([]string)
+	return languages
+}
+
+// withLanguages 将请求的语言偏好写入请求的 context
+func withLanguages(r *http.Request) *http.Request {
+	languages := ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if len(languages) == 0 {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), languagesCtxKey{}, languages))
+}
+
+// ParseAcceptLanguage 解析 Accept-Language 请求头，返回按 q 值从高到低排列的语言标签
+// q 值相同时保持请求头中的顺序，q=0 或格式不合法的条目会被忽略
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if params != "" {
+			name, value, ok := strings.Cut(strings.TrimSpace(params), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || v < 0 || v > 1 {
+				continue
+			}
+			q = v
+		}
+		if q == 0 {
+			continue
+		}
+		items = append(items, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	var languages []string
+	for _, item := range items {
+		languages = append(languages, item.tag)
+	}
+	return languages
+}
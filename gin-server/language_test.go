@@ -0,0 +1,62 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithAcceptLanguage tests injecting parsed language preferences into the context
+func TestWithAcceptLanguage(t *testing.T) {
+	var seen []string
+	r := gin.New()
+	r.GET("/greeting", WrapGetter(
+		func(ctx context.Context) (TestResponse, error) {
+			seen = LanguagesFromContext(ctx)
+			return TestResponse{}, nil
+		},
+		WithAcceptLanguage(),
+	))
+
+	t.Run("priority_order", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/greeting", nil)
+		req.Header.Set("Accept-Language", "en;q=0.8, zh-CN, fr;q=0, de;q=0.8, zh;q=0.9")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"zh-CN", "zh", "en", "de"}, seen)
+	})
+
+	t.Run("missing_header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/greeting", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, seen)
+	})
+}
+
+// TestParseAcceptLanguage tests parsing of the Accept-Language header
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{header: "", want: nil},
+		{header: "zh-CN", want: []string{"zh-CN"}},
+		{header: "*;q=0.1, ja", want: []string{"ja", "*"}},
+		{header: "en;q=abc, ko;q=2, es", want: []string{"es"}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ParseAcceptLanguage(tt.header), tt.header)
+	}
+}
@@ -0,0 +1,172 @@
+package ginserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// WithLenientNumbers 宽松的 JSON 请求体解码
+// 对目标字段为数值或布尔类型、而请求中发送的是字符串（如 "42"、"true"）的值进行转换后再绑定
+// 目标字段为字符串、而请求中发送的是数值（如 {"id":123}）时，按原始文本转换为字符串
+// 仅作用于 JSON 请求体，无法转换的值保持原样，由后续绑定报告错误
+func WithLenientNumbers() WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.lenientNumbers = true
+	}
+}
+
+// isJSONRequest 判断请求体是否按 JSON 绑定
+func isJSONRequest(c *gin.Context) bool {
+	return bodyBinding(c) == binding.JSON
+}
+
+// coerceLenientBody 按目标类型转换 JSON 请求体中的值，并用转换后的内容替换请求体
+func coerceLenientBody(c *gin.Context, t reflect.Type) error {
+	if !hasRequestBody(c.Request) || !isJSONRequest(c) {
+		return nil
+	}
+
+	body, err := peekRequestBody(c)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		// 无法解析时保留原始请求体，由绑定步骤返回错误
+		return nil
+	}
+
+	changed := false
+	data = coerceLenient(data, t, &changed)
+	if !changed {
+		// 没有需要转换的值时保留原始请求体，无需重新编码
+		return nil
+	}
+	coerced, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(coerced))
+	c.Request.ContentLength = int64(len(coerced))
+	return nil
+}
+
+// coerceLenient 递归地将 data 中的值转换为与目标类型兼容的 JSON 值，发生转换时将 changed 置为 true
+func coerceLenient(data any, t reflect.Type, changed *bool) any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data
+		}
+		coerceLenientFields(m, t, changed)
+		return m
+	case reflect.Slice, reflect.Array:
+		items, ok := data.([]any)
+		if !ok {
+			return data
+		}
+		for i, item := range items {
+			items[i] = coerceLenient(item, t.Elem(), changed)
+		}
+		return items
+	case reflect.Map:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data
+		}
+		for k, v := range m {
+			m[k] = coerceLenient(v, t.Elem(), changed)
+		}
+		return m
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if s, ok := data.(string); ok {
+			// 只接受符合 JSON 数值语法的字符串，"NaN"、"Inf"、"0x1p4"、"1_000" 等保持原样，由绑定返回 400
+			if s = strings.TrimSpace(s); isJSONNumber(s) {
+				*changed = true
+				return json.Number(s)
+			}
+		}
+	case reflect.String:
+		// 数值按原始文本转为字符串，大整数 ID 不会丢失精度
+		if n, ok := data.(json.Number); ok {
+			*changed = true
+			return n.String()
+		}
+	case reflect.Bool:
+		if s, ok := data.(string); ok {
+			if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+				*changed = true
+				return b
+			}
+		}
+	}
+	return data
+}
+
+// isJSONNumber 判断 s 是否符合 JSON 数值语法
+func isJSONNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// coerceLenientFields 按结构体字段的 json 名称转换对象中的值
+func coerceLenientFields(m map[string]any, t reflect.Type, changed *bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// 未指定 json 名称的嵌入结构体，其字段提升到当前层级
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				coerceLenientFields(m, ft, changed)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		// 与 encoding/json 一致，优先精确匹配，其次大小写不敏感匹配
+		key, ok := name, false
+		if _, ok = m[name]; !ok {
+			for k := range m {
+				if strings.EqualFold(k, name) {
+					key, ok = k, true
+					break
+				}
+			}
+		}
+		if ok {
+			m[key] = coerceLenient(m[key], field.Type, changed)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithLenientNumbers tests coercing stringified numbers and bools in JSON bodies
+func TestWithLenientNumbers(t *testing.T) {
+	type Address struct {
+		Zip int `json:"zip"`
+	}
+
+	type ProfileRequest struct {
+		ID      int64    `uri:"id"`
+		Age     int      `json:"age"`
+		Score   float64  `json:"score"`
+		Active  bool     `json:"active"`
+		Tags    []uint   `json:"tags"`
+		Address *Address `json:"address"`
+	}
+
+	newRouter := func(options ...WrapHandlerOptionFunc) *gin.Engine {
+		r := gin.New()
+		r.POST("/profiles/:id", WrapHandler(
+			func(ctx context.Context, req ProfileRequest) (ProfileRequest, error) {
+				return req, nil
+			},
+			options...,
+		))
+		return r
+	}
+
+	t.Run("strict_by_default", func(t *testing.T) {
+		body := `{"age":"42"}`
+		req := httptest.NewRequest(http.MethodPost, "/profiles/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		newRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		body := `{"age":"42","score":"9.5","active":"true","tags":["1",2],"address":{"zip":"10001"}}`
+		req := httptest.NewRequest(http.MethodPost, "/profiles/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		newRouter(WithLenientNumbers()).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"ID":1,"age":42,"score":9.5,"active":true,"tags":[1,2],"address":{"zip":10001}
+		}`, w.Body.String())
+	})
+
+	t.Run("invalid_number", func(t *testing.T) {
+		body := `{"age":"forty-two"}`
+		req := httptest.NewRequest(http.MethodPost, "/profiles/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		newRouter(WithLenientNumbers()).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("non_json_numbers", func(t *testing.T) {
+		// ParseFloat 能解析但不符合 JSON 数值语法的字符串应返回 400 而非编码失败
+		for _, body := range []string{
+			`{"score":"NaN"}`,
+			`{"score":"Inf"}`,
+			`{"score":"-Infinity"}`,
+			`{"score":"0x1p4"}`,
+			`{"age":"0x10"}`,
+			`{"age":"1_000"}`,
+		} {
+			req := httptest.NewRequest(http.MethodPost, "/profiles/1", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			newRouter(WithLenientNumbers()).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+}
+
+// TestWithLenientNumbersToString tests coercing JSON numbers into string fields
+func TestWithLenientNumbersToString(t *testing.T) {
+	type ExternalOrder struct {
+		ID    string `json:"id"`
+		RefID string `json:"ref_id"`
+		Note  string `json:"note"`
+	}
+
+	var received ExternalOrder
+	r := gin.New()
+	r.POST("/orders", WrapConsumer(func(ctx context.Context, req ExternalOrder) error {
+		received = req
+		return nil
+	}, WithLenientNumbers()))
+
+	body := `{"id":123,"ref_id":9007199254740993,"note":"ok"}`
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ExternalOrder{ID: "123", RefID: "9007199254740993", Note: "ok"}, received)
+}
@@ -0,0 +1,61 @@
+package ginserver
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maintenanceState 维护模式状态
+type maintenanceState struct {
+	retryAfter time.Duration
+}
+
+var maintenance atomic.Pointer[maintenanceState]
+
+// MaintenanceResponse 维护模式下的响应体
+type MaintenanceResponse struct {
+	Error       string `json:"error"`
+	Maintenance bool   `json:"maintenance"`
+	RetryAfter  int64  `json:"retry_after,omitempty"` // 建议重试的秒数
+}
+
+// SetMaintenance 设置全局维护模式
+// 开启后所有包装的处理器直接返回 503，不再执行解码和业务逻辑
+// retryAfter > 0 时设置 Retry-After 响应头（单位：秒，向上取整）
+func SetMaintenance(on bool, retryAfter time.Duration) {
+	if !on {
+		maintenance.Store(nil)
+		return
+	}
+	maintenance.Store(&maintenanceState{retryAfter: retryAfter})
+}
+
+// InMaintenance 返回当前是否处于维护模式
+func InMaintenance() bool {
+	return maintenance.Load() != nil
+}
+
+// abortIfMaintenance 处于维护模式时写入 503 响应并返回 true
+func abortIfMaintenance(c *gin.Context) bool {
+	state := maintenance.Load()
+	if state == nil {
+		return false
+	}
+
+	resp := MaintenanceResponse{
+		Error:       "service under maintenance",
+		Maintenance: true,
+	}
+	if state.retryAfter > 0 {
+		seconds := int64(math.Ceil(state.retryAfter.Seconds()))
+		c.Header("Retry-After", strconv.FormatInt(seconds, 10))
+		resp.RetryAfter = seconds
+	}
+	c.AbortWithStatusJSON(http.StatusServiceUnavailable, resp)
+	return true
+}
@@ -0,0 +1,49 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetMaintenance tests toggling the global maintenance mode
+func TestSetMaintenance(t *testing.T) {
+	t.Cleanup(func() { SetMaintenance(false, 0) })
+
+	executed := false
+	r := gin.New()
+	r.GET("/health", WrapGetter(
+		func(ctx context.Context) (TestResponse, error) {
+			executed = true
+			return TestResponse{Name: "ok"}, nil
+		},
+	))
+
+	SetMaintenance(true, 90*time.Second)
+	assert.True(t, InMaintenance())
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "90", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":"service under maintenance","maintenance":true,"retry_after":90}`, w.Body.String())
+	assert.False(t, executed)
+
+	SetMaintenance(false, 0)
+	assert.False(t, InMaintenance())
+
+	req = httptest.NewRequest(http.MethodGet, "/health", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Retry-After"))
+	assert.True(t, executed)
+}
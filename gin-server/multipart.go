@@ -0,0 +1,104 @@
+package ginserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MultipartMixedContentType multipart/mixed 响应的 Content-Type（不含 boundary 参数）
+const MultipartMixedContentType = "multipart/mixed"
+
+// Part multipart 响应中的一个部分
+// Reader 非 nil 时优先从 Reader 读取内容，否则使用 Data；Reader 实现了 io.Closer 时写出后自动关闭
+type Part struct {
+	ContentType string               // 该部分的 Content-Type
+	Header      textproto.MIMEHeader // 额外的部分头，如 Content-Disposition
+	Data        []byte
+	Reader      io.Reader
+}
+
+// JSONPart 将 v 编码为 application/json 类型的部分
+func JSONPart(v any) (Part, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Part{}, err
+	}
+	return Part{ContentType: jsonContentType, Data: data}, nil
+}
+
+// WrapMultipart 包装返回多个部分的处理器，响应以 multipart/mixed 格式逐个写出各部分，每写完一个部分立即刷新
+// 处理器返回的错误交给错误处理器；开始写出后的错误直接终止响应
+// 适用场景：一次返回 JSON 摘要和附带文件等捆绑结果
+func WrapMultipart[I any](
+	h func(ctx context.Context, args I) ([]Part, error),
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return WrapHandler(h, append([]WrapHandlerOptionFunc{WithEncoder(MultipartMixedEncoder())}, options...)...)
+}
+
+// MultipartMixedEncoder multipart/mixed 编码器，输出必须为 []Part
+// boundary 随机生成并写入 Content-Type 的 boundary 参数
+func MultipartMixedEncoder() EncoderFunc {
+	return func(c *gin.Context, output any) error {
+		parts, ok := output.([]Part)
+		if !ok {
+			return ErrEncoderReceivedWrongType
+		}
+		defer closeParts(parts)
+
+		mw := multipart.NewWriter(c.Writer)
+		c.Header("Content-Type", MultipartMixedContentType+"; boundary="+mw.Boundary())
+		c.Status(http.StatusOK)
+
+		for _, part := range parts {
+			if err := writePart(mw, part); err != nil {
+				// 已开始写入响应，无法再返回错误状态码，直接终止
+				c.Abort()
+				return nil
+			}
+			c.Writer.Flush()
+		}
+		if err := mw.Close(); err != nil {
+			c.Abort()
+		}
+		return nil
+	}
+}
+
+// writePart 写出一个部分的部分头和内容
+func writePart(mw *multipart.Writer, part Part) error {
+	header := make(textproto.MIMEHeader, len(part.Header)+1)
+	for k, v := range part.Header {
+		header[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
+	if part.ContentType != "" {
+		header.Set("Content-Type", part.ContentType)
+	}
+
+	w, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	reader := part.Reader
+	if reader == nil {
+		reader = bytes.NewReader(part.Data)
+	}
+	_, err = io.Copy(w, reader)
+	return err
+}
+
+// closeParts 关闭实现了 io.Closer 的部分内容
+func closeParts(parts []Part) {
+	for _, part := range parts {
+		if closer, ok := part.Reader.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
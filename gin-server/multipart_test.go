@@ -0,0 +1,61 @@
+package ginserver
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrapMultipart tests a two-part multipart/mixed response with a JSON summary and a file
+func TestWrapMultipart(t *testing.T) {
+	r := gin.New()
+	r.GET("/reports/:id", WrapMultipart(func(ctx context.Context, req TestURIRequest) ([]Part, error) {
+		summary, err := JSONPart(TestResponse{ID: req.ID, Name: "report"})
+		if err != nil {
+			return nil, err
+		}
+		file := Part{
+			ContentType: "text/csv",
+			Header:      textproto.MIMEHeader{"Content-Disposition": {`attachment; filename="report.csv"`}},
+			Reader:      io.NopCloser(strings.NewReader("id,name\n7,report\n")),
+		}
+		return []Part{summary, file}, nil
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/reports/7", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mediaType, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, MultipartMixedContentType, mediaType)
+	assert.NotEmpty(t, params["boundary"])
+
+	mr := multipart.NewReader(w.Body, params["boundary"])
+
+	part, err := mr.NextPart()
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json; charset=utf-8", part.Header.Get("Content-Type"))
+	data, _ := io.ReadAll(part)
+	assert.JSONEq(t, `{"id":7,"name":"report","email":""}`, string(data))
+
+	part, err = mr.NextPart()
+	assert.NoError(t, err)
+	assert.Equal(t, "text/csv", part.Header.Get("Content-Type"))
+	assert.Equal(t, "report.csv", part.FileName())
+	data, _ = io.ReadAll(part)
+	assert.Equal(t, "id,name\n7,report\n", string(data))
+
+	_, err = mr.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,131 @@
+package ginserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NDJSONContentType NDJSON 响应的 Content-Type
+const NDJSONContentType = "application/x-ndjson"
+
+// JSONLinesContentType JSON Lines 响应的 Content-Type
+const JSONLinesContentType = "application/jsonl"
+
+// StreamFunc 流式输出函数，通过 emit 逐条写出元素
+type StreamFunc[T any] func(emit func(item T) error) error
+
+// CountPreamble NDJSON 流的首行，携带元素总数
+type CountPreamble struct {
+	Total int64 `json:"total"`
+}
+
+// countedStreamOutput 带总数前导行的流式响应的内部输出类型
+type countedStreamOutput[T any] struct {
+	total  int64
+	stream StreamFunc[T]
+}
+
+// WrapCountedNDJSON 包装带总数前导行的 NDJSON 流式处理器
+// 处理器返回元素总数和流式输出函数，响应首行为 {"total":N}，随后每行一个元素
+// 处理器返回的错误交给错误处理器；开始写出后的错误直接终止响应
+// 适用场景：客户端需要预先知道总数的大列表流式导出
+func WrapCountedNDJSON[I, T any](
+	h func(ctx context.Context, args I) (int64, StreamFunc[T], error),
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return WrapHandler(func(ctx context.Context, args I) (countedStreamOutput[T], error) {
+		total, stream, err := h(ctx, args)
+		if err != nil {
+			return countedStreamOutput[T]{}, err
+		}
+		return countedStreamOutput[T]{total: total, stream: stream}, nil
+	}, append([]WrapHandlerOptionFunc{WithEncoder(countedNDJSONEncoder[T])}, options...)...)
+}
+
+// countedNDJSONEncoder 写出总数前导行和流中的各个元素
+func countedNDJSONEncoder[T any](c *gin.Context, output any) error {
+	out, ok := output.(countedStreamOutput[T])
+	if !ok {
+		return ErrEncoderReceivedWrongType
+	}
+
+	c.Header("Content-Type", NDJSONContentType)
+	c.Status(http.StatusOK)
+
+	enc := newNDJSONEncoder(c)
+	if err := enc.encode(CountPreamble{Total: out.total}); err != nil {
+		c.Abort()
+		return nil
+	}
+	if out.stream == nil {
+		return nil
+	}
+	if err := out.stream(func(item T) error {
+		return enc.encode(item)
+	}); err != nil {
+		// 已开始写入响应，无法再返回错误状态码，直接终止
+		c.Abort()
+	}
+	return nil
+}
+
+// WrapJSONLines 包装 JSON Lines 流式处理器
+// 处理器返回流式输出函数，每条通过 emit 写出的元素占一行，写出后立即刷新，客户端可实时看到新的元素
+// 处理器返回的错误交给错误处理器；开始写出后的错误或客户端断开直接终止响应
+// 适用场景：日志跟踪、实时事件等需要逐条推送的长连接输出
+func WrapJSONLines[I, T any](
+	h func(ctx context.Context, args I) (StreamFunc[T], error),
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	return WrapHandler(h, append([]WrapHandlerOptionFunc{WithEncoder(jsonLinesEncoder[T])}, options...)...)
+}
+
+// jsonLinesEncoder 逐行写出流中的各个元素
+func jsonLinesEncoder[T any](c *gin.Context, output any) error {
+	stream, ok := output.(StreamFunc[T])
+	if !ok {
+		return ErrEncoderReceivedWrongType
+	}
+
+	c.Header("Content-Type", JSONLinesContentType)
+	c.Status(http.StatusOK)
+	// 先刷新响应头，客户端无需等待第一条元素即可开始读取
+	c.Writer.Flush()
+
+	if stream == nil {
+		return nil
+	}
+	enc := newNDJSONEncoder(c)
+	if err := stream(func(item T) error {
+		return enc.encode(item)
+	}); err != nil {
+		// 已开始写入响应，无法再返回错误状态码，直接终止
+		c.Abort()
+	}
+	return nil
+}
+
+// ndjsonEncoder 逐行写出 JSON 并立即刷新
+type ndjsonEncoder struct {
+	c   *gin.Context
+	enc *json.Encoder
+}
+
+func newNDJSONEncoder(c *gin.Context) *ndjsonEncoder {
+	return &ndjsonEncoder{c: c, enc: json.NewEncoder(c.Writer)}
+}
+
+// encode 写出一行 JSON（json.Encoder 会自动追加换行）并刷新到客户端
+func (e *ndjsonEncoder) encode(v any) error {
+	if err := e.c.Request.Context().Err(); err != nil {
+		return err
+	}
+	if err := e.enc.Encode(v); err != nil {
+		return err
+	}
+	e.c.Writer.Flush()
+	return nil
+}
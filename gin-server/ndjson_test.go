@@ -0,0 +1,149 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrapCountedNDJSON tests NDJSON streaming with a total-count preamble
+func TestWrapCountedNDJSON(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/users", WrapCountedNDJSON(
+			func(ctx context.Context, req TestQueryRequest) (int64, StreamFunc[TestResponse], error) {
+				return 2, func(emit func(TestResponse) error) error {
+					if err := emit(TestResponse{ID: 1, Name: "Alice"}); err != nil {
+						return err
+					}
+					return emit(TestResponse{ID: 2, Name: "Bob"})
+				}, nil
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, NDJSONContentType, w.Header().Get("Content-Type"))
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, 3)
+		assert.JSONEq(t, `{"total":2}`, lines[0])
+		assert.JSONEq(t, `{"id":1,"name":"Alice","email":""}`, lines[1])
+		assert.JSONEq(t, `{"id":2,"name":"Bob","email":""}`, lines[2])
+	})
+
+	t.Run("handler_error", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/users", WrapCountedNDJSON(
+			func(ctx context.Context, req TestQueryRequest) (int64, StreamFunc[TestResponse], error) {
+				return 0, nil, errors.New("count failed")
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "count failed")
+	})
+
+	t.Run("stream_error_terminates", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/users", WrapCountedNDJSON(
+			func(ctx context.Context, req TestQueryRequest) (int64, StreamFunc[TestResponse], error) {
+				return 5, func(emit func(TestResponse) error) error {
+					if err := emit(TestResponse{ID: 1}); err != nil {
+						return err
+					}
+					return errors.New("cursor closed")
+				}, nil
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.NotContains(t, w.Body.String(), "cursor closed")
+	})
+}
+
+// flushRecorder records the body written so far on every flush
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes []string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes = append(r.flushes, r.Body.String())
+	r.ResponseRecorder.Flush()
+}
+
+// TestWrapJSONLines tests JSON Lines streaming with per-line flushing
+func TestWrapJSONLines(t *testing.T) {
+	type LogEntry struct {
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	}
+
+	t.Run("flush_per_line", func(t *testing.T) {
+		w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		r := gin.New()
+		r.GET("/logs", WrapJSONLines(
+			func(ctx context.Context, req struct{}) (StreamFunc[LogEntry], error) {
+				return func(emit func(LogEntry) error) error {
+					if err := emit(LogEntry{Level: "info", Message: "started"}); err != nil {
+						return err
+					}
+					// 第一条日志在写出第二条之前已经刷新到客户端
+					assert.Equal(t, "{\"level\":\"info\",\"message\":\"started\"}\n", w.flushes[len(w.flushes)-1])
+					return emit(LogEntry{Level: "warn", Message: "slow"})
+				}, nil
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/logs", nil)
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, JSONLinesContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, w.Body.String(), w.flushes[len(w.flushes)-1])
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.JSONEq(t, `{"level":"warn","message":"slow"}`, lines[1])
+	})
+
+	t.Run("handler_error", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/logs", WrapJSONLines(
+			func(ctx context.Context, req struct{}) (StreamFunc[LogEntry], error) {
+				return nil, errors.New("log source unavailable")
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/logs", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "log source unavailable")
+	})
+}
@@ -0,0 +1,58 @@
+package ginserver
+
+import "reflect"
+
+// WithNilSliceAsEmpty 将输出中的 nil 切片规范化为空切片
+// 避免 JSON 编码为 null，前端可始终得到 []
+// 处理顶层切片以及结构体（或结构体指针）中一层嵌套的切片字段
+func WithNilSliceAsEmpty() WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.nilSliceAsEmpty = true
+	}
+}
+
+// normalizeNilSlices 返回将 nil 切片替换为空切片后的副本，不修改处理器返回的原始对象
+func normalizeNilSlices[O any](output O) O {
+	normalizeNilSliceValue(reflect.ValueOf(&output).Elem())
+	return output
+}
+
+// normalizeNilSliceValue 对可寻址的值进行 nil 切片规范化
+func normalizeNilSliceValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+		}
+	case reflect.Struct:
+		fillNilSliceFields(v)
+	case reflect.Ptr:
+		if v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return
+		}
+		// 复制一份结构体，避免修改处理器持有的对象
+		cp := reflect.New(v.Elem().Type())
+		cp.Elem().Set(v.Elem())
+		fillNilSliceFields(cp.Elem())
+		v.Set(cp)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		// 输出类型为接口时，对其动态值的副本进行处理
+		inner := reflect.New(v.Elem().Type()).Elem()
+		inner.Set(v.Elem())
+		normalizeNilSliceValue(inner)
+		v.Set(inner)
+	}
+}
+
+// fillNilSliceFields 将可寻址结构体中导出的 nil 切片字段设置为空切片
+func fillNilSliceFields(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Slice && field.IsNil() && field.CanSet() {
+			field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+		}
+	}
+}
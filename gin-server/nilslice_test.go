@@ -0,0 +1,93 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithNilSliceAsEmpty tests normalizing nil slices to empty arrays
+func TestWithNilSliceAsEmpty(t *testing.T) {
+	type ListResponse struct {
+		Total int            `json:"total"`
+		Items []TestResponse `json:"items"`
+	}
+
+	t.Run("default_null", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/users", WrapGetter(
+			func(ctx context.Context) ([]TestResponse, error) {
+				return nil, nil
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "null", w.Body.String())
+	})
+
+	t.Run("top_level_slice", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/users", WrapGetter(
+			func(ctx context.Context) ([]TestResponse, error) {
+				return nil, nil
+			},
+			WithNilSliceAsEmpty(),
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[]", w.Body.String())
+	})
+
+	t.Run("nested_slice_field", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/users", WrapGetter(
+			func(ctx context.Context) (ListResponse, error) {
+				return ListResponse{}, nil
+			},
+			WithNilSliceAsEmpty(),
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"total":0,"items":[]}`, w.Body.String())
+	})
+
+	t.Run("pointer_output_not_mutated", func(t *testing.T) {
+		original := &ListResponse{}
+
+		r := gin.New()
+		r.GET("/users", WrapGetter(
+			func(ctx context.Context) (*ListResponse, error) {
+				return original, nil
+			},
+			WithNilSliceAsEmpty(),
+		))
+
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"total":0,"items":[]}`, w.Body.String())
+		assert.Nil(t, original.Items)
+	})
+}
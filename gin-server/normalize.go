@@ -0,0 +1,108 @@
+package ginserver
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// normalizeField 需要规范化的字段
+type normalizeField struct {
+	index int
+	kind  string
+}
+
+// normalizeFieldCache 缓存结构体类型中带有 `normalize` 标签的字段
+var normalizeFieldCache sync.Map
+
+// normalizers 支持的规范化方式
+var normalizers = map[string]func(string) string{
+	"email": normalizeEmail,
+	"url":   normalizeURL,
+}
+
+// normalizeFields 返回 t（可为多级指针）指向的结构体中需要规范化的字段
+// 仅支持 string 和 *string 类型的字段，未知的规范化方式会被忽略
+func normalizeFields(t reflect.Type) []normalizeField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	if cached, ok := normalizeFieldCache.Load(t); ok {
+		return cached.([]normalizeField)
+	}
+
+	var fields []normalizeField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		kind := field.Tag.Get("normalize")
+		if !field.IsExported() || normalizers[kind] == nil {
+			continue
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.String {
+			fields = append(fields, normalizeField{index: i, kind: kind})
+		}
+	}
+	normalizeFieldCache.Store(t, fields)
+	return fields
+}
+
+// normalizeValues 规范化 ptr 指向的结构体中带有 `normalize` 标签的字段，nil 指针保持不变
+func normalizeValues(ptr any, fields []normalizeField) {
+	v := reflect.ValueOf(ptr).Elem()
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	for _, f := range fields {
+		field := v.Field(f.index)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		field.SetString(normalizers[f.kind](field.String()))
+	}
+}
+
+// normalizeEmail 去除首尾空白并将域名部分转为小写，本地部分按 RFC 5321 区分大小写，保持不变
+func normalizeEmail(s string) string {
+	s = strings.TrimSpace(s)
+	at := strings.LastIndex(s, "@")
+	if at < 0 {
+		return s
+	}
+	return s[:at+1] + strings.ToLower(s[at+1:])
+}
+
+// normalizeURL 去除首尾空白，将协议和主机名转为小写并去掉默认端口，无法解析时仅去除空白
+func normalizeURL(s string) string {
+	s = strings.TrimSpace(s)
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return s
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+	return u.String()
+}
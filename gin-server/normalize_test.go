@@ -0,0 +1,50 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type normalizeRequest struct {
+	Email    string  `json:"email" normalize:"email" binding:"required,email"`
+	Website  *string `json:"website" normalize:"url"`
+	Nickname string  `json:"nickname" normalize:"unknown"`
+}
+
+// TestNormalizeFields tests that tagged fields are normalized before reaching the handler
+func TestNormalizeFields(t *testing.T) {
+	var received normalizeRequest
+	r := gin.New()
+	r.POST("/users", WrapConsumer(func(ctx context.Context, req normalizeRequest) error {
+		received = req
+		return nil
+	}))
+
+	body := `{"email":" Alice.Smith@Example.COM ","website":"HTTPS://Example.com:443/Profile","nickname":" Ali "}`
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Alice.Smith@example.com", received.Email)
+	if assert.NotNil(t, received.Website) {
+		assert.Equal(t, "https://example.com/Profile", *received.Website)
+	}
+	assert.Equal(t, " Ali ", received.Nickname)
+}
+
+// TestNormalizeURL tests URL canonicalization
+func TestNormalizeURL(t *testing.T) {
+	assert.Equal(t, "http://example.com/a?b=C", normalizeURL("HTTP://EXAMPLE.com:80/a?b=C"))
+	assert.Equal(t, "http://example.com:8080", normalizeURL("http://Example.com:8080"))
+	assert.Equal(t, "https://[::1]:8443/", normalizeURL("https://[::1]:8443/"))
+	assert.Equal(t, "not a url", normalizeURL(" not a url "))
+}
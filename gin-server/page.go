@@ -0,0 +1,108 @@
+package ginserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// DefaultCursorParam 游标分页默认使用的 Query 参数名
+const DefaultCursorParam = "cursor"
+
+// 页码分页的 Query 参数名及默认值
+const (
+	PageParam       = "page"
+	PageSizeParam   = "page_size"
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// CursorPageEncoder 游标分页编码器，输出类型须为 handler.CursorPage[T]
+// 响应体为 CursorPage 的 JSON；还有下一页时设置 Link: <下一页地址>; rel="next"，
+// 下一页地址为当前请求地址，并将 Query 参数 cursorParam 替换为 NextCursor，cursorParam 为空时使用 DefaultCursorParam
+// 用法：WrapHandler(listUsers, WithEncoder(CursorPageEncoder[User]("")))
+func CursorPageEncoder[T any](cursorParam string) EncoderFunc {
+	if cursorParam == "" {
+		cursorParam = DefaultCursorParam
+	}
+	return func(c *gin.Context, output any) error {
+		page, ok := output.(handler.CursorPage[T])
+		if !ok {
+			return ErrEncoderReceivedWrongType
+		}
+		if page.HasMore && page.NextCursor != "" {
+			next := *c.Request.URL
+			query := next.Query()
+			query.Set(cursorParam, page.NextCursor)
+			next.RawQuery = query.Encode()
+			c.Header("Link", "<"+next.RequestURI()+`>; rel="next"`)
+		}
+		c.JSON(http.StatusOK, page)
+		return nil
+	}
+}
+
+// PageConfig WrapPaginated 的配置
+type PageConfig struct {
+	DefaultSize int // 默认每页条数，为 0 时使用 DefaultPageSize
+	MaxSize     int // 每页条数上限，为 0 时使用 MaxPageSize，小于 0 表示不限制
+}
+
+// WrapPaginated 包装基于页码的列表处理器
+// 从 Query 参数 page、page_size 解析分页参数：page 小于 1 时为 1，page_size 缺失或小于 1 时使用默认值，超过上限时取上限；
+// 不是整数时返回 handler.ErrBadRequest。处理器返回当前页的条目和总数，响应体为带有总页数的 handler.PageResponse[T]
+// 其余输入参数与 WrapHandler 一样绑定到 I
+// 适用场景：需要 page/page_size/total/total_pages 约定的列表接口
+func WrapPaginated[I, T any](
+	h func(ctx context.Context, args I, page handler.Page) ([]T, int64, error),
+	config PageConfig,
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	if config.DefaultSize == 0 {
+		config.DefaultSize = DefaultPageSize
+	}
+	if config.MaxSize == 0 {
+		config.MaxSize = MaxPageSize
+	}
+
+	return wrapHandler(func(c *gin.Context, args I) (handler.PageResponse[T], error) {
+		page, err := parsePage(c, config.DefaultSize, config.MaxSize)
+		if err != nil {
+			return handler.PageResponse[T]{}, err
+		}
+		items, total, err := h(c.Request.Context(), args, page)
+		if err != nil {
+			return handler.PageResponse[T]{}, err
+		}
+		return handler.NewPageResponse(items, page, total), nil
+	}, options...)
+}
+
+// parsePage 从 Query 参数解析分页参数
+func parsePage(c *gin.Context, defaultSize, maxSize int) (handler.Page, error) {
+	page := handler.Page{Number: 1, Size: defaultSize}
+	if v := c.Query(PageParam); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return handler.Page{}, fmt.Errorf("%w: invalid %s %q", handler.ErrBadRequest, PageParam, v)
+		}
+		page.Number = max(n, 1)
+	}
+	if v := c.Query(PageSizeParam); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return handler.Page{}, fmt.Errorf("%w: invalid %s %q", handler.ErrBadRequest, PageSizeParam, v)
+		}
+		if n >= 1 {
+			page.Size = n
+		}
+	}
+	if maxSize > 0 && page.Size > maxSize {
+		page.Size = maxSize
+	}
+	return page, nil
+}
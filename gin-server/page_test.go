@@ -0,0 +1,126 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+type cursorPageRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+// TestCursorPageEncoder tests the cursor page body and the next Link header
+func TestCursorPageEncoder(t *testing.T) {
+	r := gin.New()
+	r.GET("/users", WrapHandler(
+		func(ctx context.Context, req cursorPageRequest) (handler.CursorPage[TestResponse], error) {
+			if req.Cursor == "last" {
+				return handler.CursorPage[TestResponse]{}, nil
+			}
+			return handler.CursorPage[TestResponse]{
+				Items:      []TestResponse{{ID: 1, Name: "Alice"}},
+				NextCursor: "abc+/=",
+				HasMore:    true,
+			}, nil
+		},
+		WithEncoder(CursorPageEncoder[TestResponse]("")),
+	))
+
+	t.Run("has_more", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?limit=1&cursor=first", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"items":[{"id":1,"name":"Alice","email":""}],"next_cursor":"abc+/=","has_more":true}`, w.Body.String())
+		assert.Equal(t, `</users?cursor=abc%2B%2F%3D&limit=1>; rel="next"`, w.Header().Get("Link"))
+	})
+
+	t.Run("last_page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users?cursor=last", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"items":[],"next_cursor":"","has_more":false}`, w.Body.String())
+		assert.Empty(t, w.Header().Get("Link"))
+	})
+}
+
+// TestWrapPaginated tests page parsing, clamping and total_pages computation
+func TestWrapPaginated(t *testing.T) {
+	type ListUsersRequest struct {
+		Role string `form:"role"`
+	}
+
+	var gotPage handler.Page
+	var gotRole string
+	list := func(ctx context.Context, req ListUsersRequest, page handler.Page) ([]TestResponse, int64, error) {
+		gotPage, gotRole = page, req.Role
+		if req.Role == "none" {
+			return nil, 0, nil
+		}
+		return []TestResponse{{ID: int64(page.Offset() + 1), Name: "Alice"}}, 42, nil
+	}
+
+	r := gin.New()
+	r.GET("/users", WrapPaginated(list, PageConfig{}))
+	r.GET("/small", WrapPaginated(list, PageConfig{DefaultSize: 5, MaxSize: 20}))
+
+	serve := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		w := serve("/users?role=admin")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, handler.Page{Number: 1, Size: DefaultPageSize}, gotPage)
+		assert.Equal(t, "admin", gotRole)
+		assert.JSONEq(t, `{
+			"items":[{"id":1,"name":"Alice","email":""}],
+			"page":1,"page_size":10,"total":42,"total_pages":5
+		}`, w.Body.String())
+	})
+
+	t.Run("explicit_page", func(t *testing.T) {
+		w := serve("/users?page=3&page_size=20")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, handler.Page{Number: 3, Size: 20}, gotPage)
+		assert.Contains(t, w.Body.String(), `"id":41`)
+		assert.Contains(t, w.Body.String(), `"total_pages":3`)
+	})
+
+	t.Run("clamped", func(t *testing.T) {
+		serve("/users?page=0&page_size=1000")
+		assert.Equal(t, handler.Page{Number: 1, Size: MaxPageSize}, gotPage)
+
+		serve("/small?page_size=50")
+		assert.Equal(t, handler.Page{Number: 1, Size: 20}, gotPage)
+
+		serve("/small?page_size=-1")
+		assert.Equal(t, handler.Page{Number: 1, Size: 5}, gotPage)
+	})
+
+	t.Run("empty_items", func(t *testing.T) {
+		w := serve("/users?role=none")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"items":[],"page":1,"page_size":10,"total":0,"total_pages":0}`, w.Body.String())
+	})
+
+	t.Run("invalid_page", func(t *testing.T) {
+		w := serve("/users?page=abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid page")
+	})
+}
@@ -0,0 +1,87 @@
+package ginserver
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// PathParamError 路径参数无法转换为字段类型时的错误，默认错误处理器返回 400
+type PathParamError struct {
+	Param string // 路径参数名
+	Value string // 请求中的原始值
+	Err   error  // 底层的转换错误
+}
+
+func (e *PathParamError) Error() string {
+	return fmt.Sprintf("invalid path parameter %q: %q: %v", e.Param, e.Value, e.Err)
+}
+
+func (e *PathParamError) Unwrap() error {
+	return e.Err
+}
+
+// Details 返回出错的路径参数及其原始值
+func (e *PathParamError) Details() []handler.ErrorDetail {
+	return []handler.ErrorDetail{{Field: e.Param, Issue: fmt.Sprintf("invalid value %q", e.Value)}}
+}
+
+// findPathParamError 在 ShouldBindUri 失败后定位无法转换的路径参数
+// 仅检查数值和布尔类型的 `uri` 字段，找不到时返回 nil，由调用方返回原始错误
+func findPathParamError(params gin.Params, t reflect.Type, tag string) *PathParamError {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			if err := findPathParamError(params, field.Type, tag); err != nil {
+				return err
+			}
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		value, ok := params.Get(name)
+		if !ok || value == "" {
+			continue
+		}
+		if err := parsePathParam(value, field.Type); err != nil {
+			return &PathParamError{Param: name, Value: value, Err: err}
+		}
+	}
+	return nil
+}
+
+// parsePathParam 按字段类型尝试解析路径参数，与 gin 表单绑定的转换规则一致
+func parsePathParam(value string, t reflect.Type) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var err error
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// time.Duration 等特殊类型由 gin 自行处理
+		if t.PkgPath() == "" {
+			_, err = strconv.ParseInt(value, 10, t.Bits())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, err = strconv.ParseUint(value, 10, t.Bits())
+	case reflect.Bool:
+		_, err = strconv.ParseBool(value)
+	case reflect.Float32, reflect.Float64:
+		_, err = strconv.ParseFloat(value, t.Bits())
+	}
+	return err
+}
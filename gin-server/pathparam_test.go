@@ -0,0 +1,69 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPathParamError tests that invalid path parameters produce a typed 400 error
+func TestPathParamError(t *testing.T) {
+	type pathRequest struct {
+		ID     int64  `uri:"id"`
+		Page   uint   `uri:"page"`
+		Active bool   `uri:"active"`
+		Name   string `uri:"name"`
+	}
+
+	var captured error
+	r := gin.New()
+	r.GET("/users/:id/:page/:active/:name", WrapConsumer(
+		func(ctx context.Context, req pathRequest) error {
+			return nil
+		},
+		WithErrorObserver(func(c *gin.Context, err error) {
+			captured = err
+		}),
+	))
+
+	tests := []struct {
+		name  string
+		path  string
+		param string
+		value string
+	}{
+		{name: "int", path: "/users/abc/1/true/alice", param: "id", value: "abc"},
+		{name: "uint", path: "/users/1/-1/true/alice", param: "page", value: "-1"},
+		{name: "bool", path: "/users/1/1/maybe/alice", param: "active", value: "maybe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captured = nil
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var pathErr *PathParamError
+			assert.True(t, errors.As(captured, &pathErr))
+			assert.Equal(t, tt.param, pathErr.Param)
+			assert.Equal(t, tt.value, pathErr.Value)
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users/1/2/false/alice", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -3,6 +3,7 @@ package ginserver
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -131,3 +132,70 @@ func TestPointerTypes(t *testing.T) {
 		assert.Equal(t, "null", w.Body.String())
 	})
 }
+
+// TestWithNilOutputStatus tests mapping a nil pointer output to a configured status instead of 200 null
+func TestWithNilOutputStatus(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+	getUser := func(ctx context.Context, req TestURIRequest) (*User, error) {
+		if req.ID == 1 {
+			return &User{Name: "Alice"}, nil
+		}
+		return nil, nil
+	}
+
+	r := gin.New()
+	r.GET("/default/:id", WrapHandler(getUser))
+	r.GET("/users/:id", WrapHandler(getUser, WithNilOutputStatus(http.StatusNotFound)))
+	r.GET("/custom/:id", WrapHandler(getUser, WithNilOutputStatus(http.StatusNotFound), WithErrorHandler(func(c *gin.Context, err error) {
+		var nilErr *NilOutputError
+		if errors.As(err, &nilErr) {
+			c.JSON(nilErr.Status, gin.H{"code": "USER_NOT_FOUND"})
+			return
+		}
+		DefaultErrorHandler()(c, err)
+	})))
+	r.GET("/empty/:id", WrapHandler(getUser, WithNilOutputStatus(http.StatusNoContent)))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("default_null", func(t *testing.T) {
+		w := serve("/default/2")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "null", w.Body.String())
+	})
+
+	t.Run("found", func(t *testing.T) {
+		w := serve("/users/1")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"name":"Alice"}`, w.Body.String())
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		w := serve("/users/2")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error":"not found"}`, w.Body.String())
+	})
+
+	t.Run("custom_body", func(t *testing.T) {
+		w := serve("/custom/2")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"code":"USER_NOT_FOUND"}`, w.Body.String())
+	})
+
+	t.Run("no_content", func(t *testing.T) {
+		w := serve("/empty/2")
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+}
@@ -0,0 +1,177 @@
+package ginserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// DefaultDiscriminator 多态字段未通过 `discriminator` 标签指定时使用的类型标识字段名
+const DefaultDiscriminator = "type"
+
+// 错误定义
+var ErrUnknownDiscriminator = errors.New("unknown discriminator")
+
+// polymorphicConfig WithPolymorphic 注册的多态字段
+type polymorphicConfig struct {
+	field    string
+	registry map[string]func() any
+}
+
+// WithPolymorphic 将 JSON 请求体中名为 field 的接口类型字段按类型标识解码为具体类型
+// 类型标识取自同一对象中 `discriminator` 标签指定的字段（默认为 DefaultDiscriminator），
+// 根据其值在 registry 中查找工厂函数创建目标对象，再将 field 的内容反序列化到该对象中；
+// 工厂函数返回指针时字段保存指针，返回值类型时字段保存值。类型标识未注册时返回 ErrUnknownDiscriminator（同时匹配 handler.ErrBadRequest）
+// 仅作用于默认解码器和结构体输入的顶层字段，可多次调用以注册多个多态字段
+// 适用场景：payload 的结构由 type 决定的带标签联合类型请求体
+func WithPolymorphic(field string, registry map[string]func() any) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.polymorphic = append(opts.polymorphic, polymorphicConfig{field: field, registry: registry})
+	}
+}
+
+// polymorphicValue 从请求体中取出的多态字段
+type polymorphicValue struct {
+	config        polymorphicConfig
+	index         int
+	discriminator string
+	raw           json.RawMessage
+}
+
+// extractPolymorphic 从 JSON 请求体中取出多态字段并替换请求体
+// 接口类型的字段无法由 gin 直接绑定，取出后再由 setPolymorphic 按具体类型写入
+func extractPolymorphic(c *gin.Context, t reflect.Type, configs []polymorphicConfig) ([]polymorphicValue, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || !hasRequestBody(c.Request) || !isJSONRequest(c) {
+		return nil, nil
+	}
+
+	body, err := peekRequestBody(c)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(body, &m); err != nil {
+		// 无法解析时保留原始请求体，由绑定步骤返回错误
+		return nil, nil
+	}
+
+	var values []polymorphicValue
+	for _, cfg := range configs {
+		index, discriminator, ok := polymorphicField(t, cfg.field)
+		if !ok {
+			continue
+		}
+		key, ok := lookupJSONKey(m, cfg.field)
+		if !ok {
+			continue
+		}
+		values = append(values, polymorphicValue{config: cfg, index: index, raw: m[key]})
+		delete(m, key)
+
+		var disc string
+		if dk, ok := lookupJSONKey(m, discriminator); ok {
+			if err := json.Unmarshal(m[dk], &disc); err != nil {
+				return nil, fmt.Errorf("%w: %s must be a string: %w", handler.ErrBadRequest, discriminator, err)
+			}
+		}
+		values[len(values)-1].discriminator = disc
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	stripped, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(stripped))
+	c.Request.ContentLength = int64(len(stripped))
+	return values, nil
+}
+
+// setPolymorphic 按类型标识创建具体类型并写入 ptr 指向的结构体
+func setPolymorphic(ptr any, values []polymorphicValue) error {
+	v := reflect.ValueOf(ptr).Elem()
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	for _, pv := range values {
+		if len(pv.raw) == 0 || string(pv.raw) == "null" {
+			continue
+		}
+		field := v.Field(pv.index)
+		factory := pv.config.registry[pv.discriminator]
+		if factory == nil {
+			return fmt.Errorf("%w: %w %q for %s", handler.ErrBadRequest, ErrUnknownDiscriminator, pv.discriminator, pv.config.field)
+		}
+
+		obj := reflect.ValueOf(factory())
+		target := obj
+		if obj.Kind() != reflect.Ptr {
+			// 值类型需要通过指针反序列化
+			target = reflect.New(obj.Type())
+			target.Elem().Set(obj)
+		}
+		if err := json.Unmarshal(pv.raw, target.Interface()); err != nil {
+			return fmt.Errorf("%w: %s: %w", handler.ErrBadRequest, pv.config.field, err)
+		}
+		if obj.Kind() != reflect.Ptr {
+			obj = target.Elem()
+		}
+		if !obj.Type().AssignableTo(field.Type()) {
+			return fmt.Errorf("%w: %s does not implement %s", ErrDecoderReturnedWrongType, obj.Type(), field.Type())
+		}
+		field.Set(obj)
+	}
+	return nil
+}
+
+// polymorphicField 查找 JSON 名称为 name 的接口类型字段，返回字段下标和类型标识字段名
+func polymorphicField(t reflect.Type, name string) (int, string, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Type.Kind() != reflect.Interface {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+		if !strings.EqualFold(jsonName, name) {
+			continue
+		}
+		discriminator := field.Tag.Get("discriminator")
+		if discriminator == "" {
+			discriminator = DefaultDiscriminator
+		}
+		return i, discriminator, true
+	}
+	return 0, "", false
+}
+
+// lookupJSONKey 与 encoding/json 一致，优先精确匹配，其次大小写不敏感匹配
+func lookupJSONKey(m map[string]json.RawMessage, name string) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+	return "", false
+}
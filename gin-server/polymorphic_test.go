@@ -0,0 +1,91 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+type notification interface {
+	Channel() string
+}
+
+type emailNotification struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+}
+
+func (emailNotification) Channel() string { return "email" }
+
+type smsNotification struct {
+	Phone string `json:"phone"`
+}
+
+func (*smsNotification) Channel() string { return "sms" }
+
+type sendRequest struct {
+	Kind    string       `json:"kind"`
+	Payload notification `json:"payload" discriminator:"kind" binding:"required"`
+}
+
+// TestWithPolymorphic tests decoding a tagged union field by its discriminator
+func TestWithPolymorphic(t *testing.T) {
+	var received sendRequest
+	var decodeErr error
+	r := gin.New()
+	r.POST("/notify", WrapConsumer(func(ctx context.Context, req sendRequest) error {
+		received = req
+		return nil
+	},
+		WithPolymorphic("payload", map[string]func() any{
+			"email": func() any { return emailNotification{} },
+			"sms":   func() any { return &smsNotification{} },
+		}),
+		WithErrorObserver(func(c *gin.Context, err error) { decodeErr = err }),
+	))
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/notify", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("email", func(t *testing.T) {
+		w := serve(`{"kind":"email","payload":{"to":"alice@example.com","subject":"hi"}}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "email", received.Kind)
+		assert.Equal(t, emailNotification{To: "alice@example.com", Subject: "hi"}, received.Payload)
+	})
+
+	t.Run("sms", func(t *testing.T) {
+		w := serve(`{"kind":"sms","payload":{"phone":"+8613800000000"}}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, &smsNotification{Phone: "+8613800000000"}, received.Payload)
+		assert.Equal(t, "sms", received.Payload.Channel())
+	})
+
+	t.Run("unknown_discriminator", func(t *testing.T) {
+		w := serve(`{"kind":"pigeon","payload":{}}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.True(t, errors.Is(decodeErr, ErrUnknownDiscriminator))
+		assert.True(t, errors.Is(decodeErr, handler.ErrBadRequest))
+	})
+
+	t.Run("missing_payload", func(t *testing.T) {
+		w := serve(`{"kind":"email"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
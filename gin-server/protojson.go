@@ -0,0 +1,57 @@
+package ginserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoJSONDecoder 使用 protojson 将 JSON 请求体解码为 proto 消息，I 为生成的消息指针类型（如 *pb.CreateUserRequest）
+// 字段名同时支持 proto 原始名称（snake_case）和 JSON 名称（lowerCamelCase），未知字段会被忽略；
+// 没有请求体时返回空消息，JSON 不合法时返回 handler.ErrBadRequest
+// 用法：WrapHandler(createUser, WithDecoder(ProtoJSONDecoder[*pb.CreateUserRequest]()))
+func ProtoJSONDecoder[I proto.Message]() DecoderFunc {
+	unmarshal := protojson.UnmarshalOptions{DiscardUnknown: true}
+	return func(c *gin.Context) (any, error) {
+		var zero I
+		msg := zero.ProtoReflect().Type().New().Interface().(I)
+		if !hasRequestBody(c.Request) {
+			return msg, nil
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return nil, err
+		}
+		if len(body) == 0 {
+			return msg, nil
+		}
+		if err := unmarshal.Unmarshal(body, msg); err != nil {
+			return nil, fmt.Errorf("%w: %w", handler.ErrBadRequest, err)
+		}
+		return msg, nil
+	}
+}
+
+// ProtoJSONEncoder 使用 protojson 将 proto 消息编码为 JSON，字段名使用 proto 原始名称（snake_case），与 ProtoJSONDecoder 对应
+// 输出不是 proto.Message 时返回 ErrEncoderReceivedWrongType
+func ProtoJSONEncoder() EncoderFunc {
+	marshal := protojson.MarshalOptions{UseProtoNames: true}
+	return func(c *gin.Context, output any) error {
+		msg, ok := output.(proto.Message)
+		if !ok {
+			return ErrEncoderReceivedWrongType
+		}
+		data, err := marshal.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		c.Data(http.StatusOK, jsonContentType, data)
+		return nil
+	}
+}
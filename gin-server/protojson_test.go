@@ -0,0 +1,62 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+// TestProtoJSONDecoder tests a JSON round trip through a proto message with snake_case fields
+func TestProtoJSONDecoder(t *testing.T) {
+	var received *apipb.Method
+	r := gin.New()
+	r.POST("/methods", WrapHandler(
+		func(ctx context.Context, req *apipb.Method) (*apipb.Method, error) {
+			received = req
+			req.ResponseStreaming = true
+			return req, nil
+		},
+		WithDecoder(ProtoJSONDecoder[*apipb.Method]()),
+		WithEncoder(ProtoJSONEncoder()),
+	))
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/methods", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("snake_case_round_trip", func(t *testing.T) {
+		w := serve(`{"name":"GetUser","request_type_url":"type.googleapis.com/GetUserRequest","request_streaming":false,"unknown":1}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "GetUser", received.GetName())
+		assert.Equal(t, "type.googleapis.com/GetUserRequest", received.GetRequestTypeUrl())
+		assert.JSONEq(t, `{
+			"name":"GetUser",
+			"request_type_url":"type.googleapis.com/GetUserRequest",
+			"response_streaming":true
+		}`, w.Body.String())
+	})
+
+	t.Run("json_names", func(t *testing.T) {
+		w := serve(`{"name":"ListUsers","responseTypeUrl":"type.googleapis.com/ListUsersResponse"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "type.googleapis.com/ListUsersResponse", received.GetResponseTypeUrl())
+	})
+
+	t.Run("invalid_json", func(t *testing.T) {
+		w := serve(`{"name":1}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
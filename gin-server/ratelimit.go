@@ -0,0 +1,133 @@
+package ginserver
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// ErrRateLimited 请求被限流，默认错误处理器返回 429
+var ErrRateLimited = errors.New("rate limited")
+
+// DefaultRateLimitKeys WithKeyedRateLimit 默认最多保留的客户端数量
+const DefaultRateLimitKeys = 10000
+
+// RateLimitedError 按客户端限流被拒绝时的错误，可通过 errors.Is(err, ErrRateLimited) 判断
+type RateLimitedError struct {
+	Key        string        // 被限流的客户端标识
+	RetryAfter time.Duration // 建议的重试等待时间
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("%v: retry after %s", ErrRateLimited, e.RetryAfter)
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// Limiter 限流器接口
+// 可适配 golang.org/x/time/rate 或分布式限流器，按 IP、用户等维度的区分由限流器自行实现
+type Limiter interface {
+	Allow(ctx context.Context) bool
+}
+
+// LimiterFunc 函数形式的限流器
+type LimiterFunc func(ctx context.Context) bool
+
+func (f LimiterFunc) Allow(ctx context.Context) bool {
+	return f(ctx)
+}
+
+// WithRateLimit 为单个路由设置限流器
+// 请求在解码前进行限流检查，被拒绝时将 ErrRateLimited 交给错误处理器
+func WithRateLimit(limiter Limiter) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.limiter = limiter
+	}
+}
+
+// WithKeyedRateLimit 按客户端标识（IP、API Key、用户 ID 等）分别进行令牌桶限流
+// keyFn 从请求中提取客户端标识，每个标识拥有独立的 rate.Limiter，速率为 limit，桶容量为 burst
+// 超出限制时设置 Retry-After 响应头（单位：秒，向上取整），并将 *RateLimitedError 交给错误处理器
+// 最多保留 DefaultRateLimitKeys 个客户端的限流状态，超出时淘汰最久未访问的客户端，避免内存无限增长
+func WithKeyedRateLimit(limit rate.Limit, burst int, keyFn func(c *gin.Context) string) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.keyedLimiter = newKeyedLimiter(limit, burst, keyFn, DefaultRateLimitKeys)
+	}
+}
+
+// keyedLimiter 按 key 区分的令牌桶限流器，使用 LRU 限制保留的 key 数量
+type keyedLimiter struct {
+	limit   rate.Limit
+	burst   int
+	keyFn   func(c *gin.Context) string
+	maxKeys int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // 元素为 *keyedLimiterEntry，最近访问的在前
+}
+
+type keyedLimiterEntry struct {
+	key     string
+	limiter *rate.Limiter
+}
+
+func newKeyedLimiter(limit rate.Limit, burst int, keyFn func(c *gin.Context) string, maxKeys int) *keyedLimiter {
+	return &keyedLimiter{
+		limit:   limit,
+		burst:   burst,
+		keyFn:   keyFn,
+		maxKeys: maxKeys,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// get 返回 key 对应的限流器，不存在时创建并在超出容量时淘汰最久未访问的 key
+func (l *keyedLimiter) get(key string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.entries[key]; ok {
+		l.lru.MoveToFront(elem)
+		return elem.Value.(*keyedLimiterEntry).limiter
+	}
+
+	entry := &keyedLimiterEntry{key: key, limiter: rate.NewLimiter(l.limit, l.burst)}
+	l.entries[key] = l.lru.PushFront(entry)
+	for l.lru.Len() > l.maxKeys {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.entries, oldest.Value.(*keyedLimiterEntry).key)
+	}
+	return entry.limiter
+}
+
+// check 检查本次请求是否允许通过，被拒绝时写入 Retry-After 响应头并返回 *RateLimitedError
+func (l *keyedLimiter) check(c *gin.Context) error {
+	key := l.keyFn(c)
+	r := l.get(key).Reserve()
+	if !r.OK() {
+		// burst 为 0 等情况下永远无法获得令牌
+		return &RateLimitedError{Key: key}
+	}
+	delay := r.Delay()
+	if delay == 0 {
+		return nil
+	}
+	// 被拒绝的请求不消耗令牌
+	r.Cancel()
+
+	c.Header("Retry-After", strconv.FormatInt(int64(math.Ceil(delay.Seconds())), 10))
+	return &RateLimitedError{Key: key, RetryAfter: delay}
+}
@@ -0,0 +1,121 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+// TestWithRateLimit tests per-route rate limiting
+func TestWithRateLimit(t *testing.T) {
+	remaining := 2
+	limiter := LimiterFunc(func(ctx context.Context) bool {
+		if remaining == 0 {
+			return false
+		}
+		remaining--
+		return true
+	})
+
+	calls := 0
+	r := gin.New()
+	r.POST("/data/sync", WrapAction(
+		func(ctx context.Context) error {
+			calls++
+			return nil
+		},
+		WithRateLimit(limiter),
+	))
+	r.POST("/data/other", WrapAction(
+		func(ctx context.Context) error {
+			return nil
+		},
+	))
+
+	codes := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/data/sync", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+		if w.Code == http.StatusTooManyRequests {
+			assert.Contains(t, w.Body.String(), ErrRateLimited.Error())
+		}
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+	assert.Equal(t, 2, calls)
+
+	// 未配置限流的路由不受影响
+	req := httptest.NewRequest(http.MethodPost, "/data/other", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestWithKeyedRateLimit tests token-bucket rate limiting keyed by client identity
+func TestWithKeyedRateLimit(t *testing.T) {
+	r := gin.New()
+	r.GET("/data", WrapAction(
+		func(ctx context.Context) error {
+			return nil
+		},
+		WithKeyedRateLimit(rate.Every(time.Minute), 2, func(c *gin.Context) string {
+			return c.GetHeader("X-API-Key")
+		}),
+	))
+
+	do := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/data", nil)
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("over_limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do("a").Code)
+		assert.Equal(t, http.StatusOK, do("a").Code)
+
+		w := do("a")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		// 每分钟补充一个令牌
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "rate limited")
+	})
+
+	t.Run("separate_keys", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do("b").Code)
+	})
+}
+
+// TestKeyedLimiterEviction tests that the number of tracked keys stays bounded
+func TestKeyedLimiterEviction(t *testing.T) {
+	l := newKeyedLimiter(rate.Every(time.Minute), 1, nil, 2)
+
+	a := l.get("a")
+	l.get("b")
+	// 访问 a 使 b 成为最久未访问的 key
+	assert.Same(t, a, l.get("a"))
+	l.get("c")
+
+	assert.Len(t, l.entries, 2)
+	assert.Contains(t, l.entries, "a")
+	assert.Contains(t, l.entries, "c")
+	assert.NotContains(t, l.entries, "b")
+}
+
+// TestRateLimitedError tests matching RateLimitedError as ErrRateLimited
+func TestRateLimitedError(t *testing.T) {
+	var err error = &RateLimitedError{Key: "a", RetryAfter: time.Second}
+
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, http.StatusTooManyRequests, StatusFromError(err))
+	assert.Equal(t, "rate limited: retry after 1s", err.Error())
+}
@@ -0,0 +1,98 @@
+package ginserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	bytesType      = reflect.TypeOf([]byte(nil))
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+	readerType     = reflect.TypeOf((*io.Reader)(nil)).Elem()
+)
+
+// RawBodyDecoder 原始请求体解码器，不做任何结构化解析
+// I 为 []byte 时读取完整的请求体，I 为 io.Reader 时直接将请求体交给处理器按需读取（处理器返回前有效）；
+// 没有请求体时分别为 nil 和 http.NoBody；I 为其他类型时返回 ErrDecoderReturnedWrongType
+// 输入类型为 []byte 或 io.Reader 且未通过 WithDecoder 指定解码器时自动使用
+// 适用场景：非 multipart 的二进制上传、原始 protobuf，以及需要对未改动的请求体做签名校验的 Webhook
+func RawBodyDecoder[I any]() DecoderFunc {
+	return func(c *gin.Context) (any, error) {
+		var args I
+		switch p := any(&args).(type) {
+		case *[]byte:
+			if !hasRequestBody(c.Request) {
+				return args, nil
+			}
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				return args, err
+			}
+			*p = body
+		case *io.Reader:
+			*p = c.Request.Body
+			if *p == nil {
+				*p = http.NoBody
+			}
+		default:
+			return nil, ErrDecoderReturnedWrongType
+		}
+		return args, nil
+	}
+}
+
+// isRawBodyType 判断输入类型是否直接接收原始请求体
+func isRawBodyType(t reflect.Type) bool {
+	return t == bytesType || t == readerType
+}
+
+// rawBodyFieldCache 缓存结构体类型中带有 `body:"raw"` 标签的字段下标
+var rawBodyFieldCache sync.Map
+
+// rawBodyFields 返回 t（可为多级指针）指向的结构体中接收原始请求体的字段下标
+// 仅支持 []byte 和 json.RawMessage 类型的字段
+func rawBodyFields(t reflect.Type) []int {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	if cached, ok := rawBodyFieldCache.Load(t); ok {
+		return cached.([]int)
+	}
+
+	var fields []int
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("body") != "raw" {
+			continue
+		}
+		if field.Type == bytesType || field.Type == rawMessageType {
+			fields = append(fields, i)
+		}
+	}
+	rawBodyFieldCache.Store(t, fields)
+	return fields
+}
+
+// setRawBodyFields 将原始请求体写入 ptr 指向的结构体中对应的字段，必要时分配 nil 指针
+func setRawBodyFields(ptr any, fields []int, body []byte) {
+	v := reflect.ValueOf(ptr).Elem()
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	for _, i := range fields {
+		field := v.Field(i)
+		// 每个字段各自持有一份拷贝，避免处理器修改时相互影响
+		field.SetBytes(append([]byte(nil), body...))
+	}
+}
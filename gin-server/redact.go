@@ -0,0 +1,167 @@
+package ginserver
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// redactCache 缓存类型是否包含需要脱敏的字段
+var redactCache sync.Map
+
+// RedactMask 脱敏时替换字符使用的掩码
+const RedactMask = '*'
+
+// Redact 返回用于日志输出的脱敏副本，不修改原始对象
+// 带有 `log:"-"` 标签的字段被省略；带有 `redact:"partial"` 标签的字段只保留首尾各一个字符，其余替换为 *
+// 包含上述标签的结构体转换为以 JSON 字段名为 key 的 map，支持嵌套结构体、指针、切片和 map；不含标签的值原样返回
+func Redact(v any) any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if !needsRedaction(rv.Type()) {
+		return v
+	}
+	return redactValue(rv)
+}
+
+// MaskPartial 只保留 s 的首尾各一个字符，其余替换为掩码；长度不超过 2 时全部替换
+func MaskPartial(s string) string {
+	runes := []rune(s)
+	if len(runes) <= 2 {
+		return strings.Repeat(string(RedactMask), len(runes))
+	}
+	return string(runes[0]) + strings.Repeat(string(RedactMask), len(runes)-2) + string(runes[len(runes)-1])
+}
+
+// needsRedaction 判断类型中是否存在需要省略或脱敏的字段
+func needsRedaction(t reflect.Type) bool {
+	if cached, ok := redactCache.Load(t); ok {
+		return cached.(bool)
+	}
+	needs := buildNeedsRedaction(t, map[reflect.Type]bool{})
+	redactCache.Store(t, needs)
+	return needs
+}
+
+// buildNeedsRedaction 递归检查类型，visiting 用于防止递归类型无限展开
+func buildNeedsRedaction(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return buildNeedsRedaction(t.Elem(), visiting)
+	case reflect.Struct:
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get("log") == "-" || field.Tag.Get("redact") == "partial" {
+				return true
+			}
+			if buildNeedsRedaction(field.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactValue 按标签生成脱敏后的值
+func redactValue(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if !needsRedaction(v.Type()) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		out := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value())
+		}
+		return out
+	case reflect.Struct:
+		out := make(map[string]any)
+		redactStruct(v, out)
+		return out
+	}
+	return v.Interface()
+}
+
+// redactStruct 将结构体字段写入 out，未指定 JSON 字段名的匿名结构体字段与 encoding/json 一样展开
+func redactStruct(v reflect.Value, out map[string]any) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("log") == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if field.Anonymous && name == "" {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				redactStruct(fv, out)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if field.Tag.Get("redact") == "partial" {
+			out[name] = redactPartial(fv)
+			continue
+		}
+		out[name] = redactValue(fv)
+	}
+}
+
+// redactPartial 对字段值做部分脱敏，nil 指针保持为 nil
+func redactPartial(v reflect.Value) any {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String {
+		return MaskPartial(v.String())
+	}
+	return MaskPartial(fmt.Sprint(v.Interface()))
+}
@@ -0,0 +1,67 @@
+package ginserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type RedactCredentials struct {
+	Token  string `json:"token" redact:"partial"`
+	Secret string `json:"secret" log:"-"`
+}
+
+type redactRequest struct {
+	RedactCredentials
+	User     string              `json:"user"`
+	Password *string             `json:"password,omitempty" redact:"partial"`
+	Keys     []RedactCredentials `json:"keys"`
+	Internal string              `json:"-"`
+}
+
+// TestRedact tests omitting and masking tagged fields for log output
+func TestRedact(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		password := "hunter2"
+		req := redactRequest{
+			RedactCredentials: RedactCredentials{Token: "abcdef", Secret: "s3cret"},
+			User:              "alice",
+			Password:          &password,
+			Keys:              []RedactCredentials{{Token: "xy", Secret: "k"}},
+			Internal:          "hidden",
+		}
+
+		assert.Equal(t, map[string]any{
+			"token":    "a****f",
+			"user":     "alice",
+			"password": "h*****2",
+			"keys":     []any{map[string]any{"token": "**"}},
+		}, Redact(req))
+		// 原始对象不被修改
+		assert.Equal(t, "abcdef", req.Token)
+	})
+
+	t.Run("nil_pointer", func(t *testing.T) {
+		assert.Equal(t, map[string]any{
+			"token":    "",
+			"user":     "bob",
+			"password": nil,
+			"keys":     nil,
+		}, Redact(&redactRequest{User: "bob"}))
+	})
+
+	t.Run("untagged_value_unchanged", func(t *testing.T) {
+		req := TestRequest{Name: "Alice", Email: "alice@example.com"}
+		assert.Equal(t, req, Redact(req))
+		assert.Nil(t, Redact(nil))
+	})
+}
+
+// TestMaskPartial tests keeping only the first and last characters
+func TestMaskPartial(t *testing.T) {
+	assert.Equal(t, "", MaskPartial(""))
+	assert.Equal(t, "*", MaskPartial("a"))
+	assert.Equal(t, "**", MaskPartial("ab"))
+	assert.Equal(t, "a*c", MaskPartial("abc"))
+	assert.Equal(t, "密**码", MaskPartial("密钥口码"))
+}
@@ -0,0 +1,193 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// RouteKind 路由处理器类型
+type RouteKind int
+
+const (
+	// RouteKindAuto 根据方法签名自动推断处理器类型
+	RouteKindAuto RouteKind = iota
+	// RouteKindHandler 有输入有输出：func(ctx, I) (O, error)
+	RouteKindHandler
+	// RouteKindGetter 只有输出：func(ctx) (O, error)
+	RouteKindGetter
+	// RouteKindConsumer 只有输入：func(ctx, I) error
+	RouteKindConsumer
+	// RouteKindAction 无输入输出：func(ctx) error
+	RouteKindAction
+)
+
+func (k RouteKind) String() string {
+	switch k {
+	case RouteKindAuto:
+		return "auto"
+	case RouteKindHandler:
+		return "handler"
+	case RouteKindGetter:
+		return "getter"
+	case RouteKindConsumer:
+		return "consumer"
+	case RouteKindAction:
+		return "action"
+	default:
+		return fmt.Sprintf("RouteKind(%d)", int(k))
+	}
+}
+
+// Route 描述一条需要注册的路由
+type Route struct {
+	Method     string                  // HTTP 方法
+	Path       string                  // 路由路径
+	MethodName string                  // 服务对象上的方法名
+	Kind       RouteKind               // 期望的处理器类型，零值表示自动推断
+	Options    []WrapHandlerOptionFunc // 该路由的包装选项
+}
+
+// 错误定义
+var ErrMethodNotFound = errors.New("method not found")
+var ErrUnsupportedSignature = errors.New("unsupported method signature")
+var ErrRouteKindMismatch = errors.New("route kind mismatch")
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Register 使用 WrapHandler 包装处理器并注册到 r 的 method path 路由上，等价于 r.Handle(method, path, WrapHandler(h, options...))
+// 路由与处理器写在同一处，也便于在调用方统一追加跨路由的公共选项
+func Register[I, O any](
+	r gin.IRouter,
+	method, path string,
+	h handler.HandlerFunc[I, O],
+	options ...WrapHandlerOptionFunc,
+) gin.IRoutes {
+	return r.Handle(method, path, WrapHandler(h, options...))
+}
+
+// RegisterService 根据路由表将服务对象的方法注册到路由
+// 通过反射检查方法签名，自动选择 WrapHandler/WrapGetter/WrapConsumer/WrapAction 对应的包装方式
+// 当方法不存在、签名不受支持或与 Route.Kind 不一致时返回错误，且不会注册任何路由
+func RegisterService(r gin.IRouter, svc any, routes []Route) error {
+	v := reflect.ValueOf(svc)
+	handlers := make([]gin.HandlerFunc, len(routes))
+
+	for i, route := range routes {
+		m := v.MethodByName(route.MethodName)
+		if !m.IsValid() {
+			return fmt.Errorf("%w: %s", ErrMethodNotFound, route.MethodName)
+		}
+
+		kind, err := routeKindOf(m.Type())
+		if err != nil {
+			return fmt.Errorf("%s: %w", route.MethodName, err)
+		}
+		if route.Kind != RouteKindAuto && route.Kind != kind {
+			return fmt.Errorf("%w: %s is %s, expected %s", ErrRouteKindMismatch, route.MethodName, kind, route.Kind)
+		}
+
+		handlers[i] = wrapMethod(m, kind, route.Options...)
+	}
+
+	for i, route := range routes {
+		r.Handle(route.Method, route.Path, handlers[i])
+	}
+	return nil
+}
+
+// routeKindOf 根据函数签名推断处理器类型
+func routeKindOf(t reflect.Type) (RouteKind, error) {
+	// 可变参数方法的最后一个参数是切片，无法与请求绑定对应
+	if t.IsVariadic() {
+		return 0, ErrUnsupportedSignature
+	}
+	if t.NumIn() < 1 || t.NumIn() > 2 || t.In(0) != contextType {
+		return 0, ErrUnsupportedSignature
+	}
+	if t.NumOut() < 1 || t.NumOut() > 2 || t.Out(t.NumOut()-1) != errorType {
+		return 0, ErrUnsupportedSignature
+	}
+
+	hasInput := t.NumIn() == 2
+	hasOutput := t.NumOut() == 2
+	switch {
+	case hasInput && hasOutput:
+		return RouteKindHandler, nil
+	case hasOutput:
+		return RouteKindGetter, nil
+	case hasInput:
+		return RouteKindConsumer, nil
+	default:
+		return RouteKindAction, nil
+	}
+}
+
+// wrapMethod 使用反射将方法包装为 gin.HandlerFunc
+func wrapMethod(m reflect.Value, kind RouteKind, options ...WrapHandlerOptionFunc) gin.HandlerFunc {
+	t := m.Type()
+
+	// 无输入时与 WrapGetter/WrapAction 一致，使用 struct{} 作为输入
+	inType := reflect.TypeOf(struct{}{})
+	if kind == RouteKindHandler || kind == RouteKindConsumer {
+		inType = t.In(1)
+	}
+
+	h := func(ctx context.Context, input any) (any, error) {
+		in := []reflect.Value{reflect.ValueOf(ctx)}
+		if kind == RouteKindHandler || kind == RouteKindConsumer {
+			// 自定义解码器可能返回与方法参数不一致的类型；io.Reader 等接口参数接收其实现类型
+			arg := reflect.ValueOf(input)
+			if !arg.IsValid() || !arg.Type().AssignableTo(inType) {
+				return nil, ErrDecoderReturnedWrongType
+			}
+			in = append(in, arg)
+		}
+		out := m.Call(in)
+
+		errValue := out[len(out)-1]
+		if !errValue.IsNil() {
+			return nil, errValue.Interface().(error)
+		}
+		if kind == RouteKindHandler || kind == RouteKindGetter {
+			return out[0].Interface(), nil
+		}
+		return struct{}{}, nil
+	}
+
+	// 按方法参数的实际类型选择默认解码器并执行 WithLenientNumbers 等依赖输入类型的步骤，用户传入的 WithDecoder 仍可覆盖
+	opts := append([]WrapHandlerOptionFunc{withInputType(inType)}, options...)
+	return WrapHandler(h, opts...)
+}
+
+// withInputType 设置处理器的实际输入类型
+func withInputType(t reflect.Type) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.inputType = t
+	}
+}
+
+// reflectDecoder 根据运行时类型创建解码器，绑定选项和原始请求体的处理与 WrapHandler 的默认解码器一致
+func reflectDecoder(t reflect.Type, bo bindOptions) DecoderFunc {
+	switch t {
+	case bytesType:
+		return RawBodyDecoder[[]byte]()
+	case readerType:
+		return RawBodyDecoder[io.Reader]()
+	}
+	return func(c *gin.Context) (any, error) {
+		ptr := reflect.New(t)
+		if err := bindRequestWith(c, ptr.Interface(), bo); err != nil {
+			return nil, classifyBindError(err)
+		}
+		return ptr.Elem().Interface(), nil
+	}
+}
@@ -0,0 +1,229 @@
+package ginserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type testService struct {
+	triggered bool
+	deleted   int64
+}
+
+func (s *testService) CreateUser(ctx context.Context, req TestRequest) (TestResponse, error) {
+	return TestResponse{ID: 1, Name: req.Name, Email: req.Email}, nil
+}
+
+func (s *testService) Health(ctx context.Context) (TestResponse, error) {
+	return TestResponse{Name: "ok"}, nil
+}
+
+func (s *testService) DeleteUser(ctx context.Context, req TestURIRequest) error {
+	s.deleted = req.ID
+	return nil
+}
+
+func (s *testService) Trigger(ctx context.Context) error {
+	s.triggered = true
+	return errors.New("trigger failed")
+}
+
+// registerOptionsRequest 使用非默认标签且请求体中的数值以字符串发送
+type registerOptionsRequest struct {
+	ID     string `json:"id"`
+	Count  int    `json:"count" binding:"required"`
+	Filter string `q:"filter"`
+}
+
+func (s *testService) Search(ctx context.Context, req registerOptionsRequest) (registerOptionsRequest, error) {
+	return req, nil
+}
+
+func (s *testService) Upload(ctx context.Context, body []byte) (int, error) {
+	return len(body), nil
+}
+
+func (s *testService) BadSignature(name string) error {
+	return nil
+}
+
+func (s *testService) Variadic(ctx context.Context, names ...string) error {
+	return nil
+}
+
+// TestRegisterService tests registering routes from a service struct
+func TestRegisterService(t *testing.T) {
+	svc := &testService{}
+	r := gin.New()
+
+	err := RegisterService(r, svc, []Route{
+		{Method: http.MethodPost, Path: "/users", MethodName: "CreateUser", Kind: RouteKindHandler},
+		{Method: http.MethodGet, Path: "/health", MethodName: "Health"},
+		{Method: http.MethodDelete, Path: "/users/:id", MethodName: "DeleteUser", Kind: RouteKindConsumer},
+		{Method: http.MethodPost, Path: "/tasks", MethodName: "Trigger", Options: []WrapHandlerOptionFunc{
+			WithErrorHandler(func(c *gin.Context, err error) {
+				c.JSON(http.StatusBadRequest, gin.H{"custom": err.Error()})
+			}),
+		}},
+	})
+	assert.NoError(t, err)
+
+	t.Run("handler", func(t *testing.T) {
+		body := `{"name":"Alice","email":"alice@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp TestResponse
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), resp.ID)
+		assert.Equal(t, "Alice", resp.Name)
+	})
+
+	t.Run("handler_binding_error", func(t *testing.T) {
+		body := `{"name":"Alice"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("getter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"ok"`)
+	})
+
+	t.Run("consumer", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/users/7", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int64(7), svc.deleted)
+	})
+
+	t.Run("action_with_options", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.True(t, svc.triggered)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "trigger failed")
+	})
+}
+
+// TestRegisterServiceErrors tests the registration-time checks of RegisterService
+func TestRegisterServiceErrors(t *testing.T) {
+	svc := &testService{}
+
+	t.Run("method_not_found", func(t *testing.T) {
+		err := RegisterService(gin.New(), svc, []Route{
+			{Method: http.MethodGet, Path: "/missing", MethodName: "Missing"},
+		})
+		assert.ErrorIs(t, err, ErrMethodNotFound)
+	})
+
+	t.Run("unsupported_signature", func(t *testing.T) {
+		err := RegisterService(gin.New(), svc, []Route{
+			{Method: http.MethodGet, Path: "/bad", MethodName: "BadSignature"},
+		})
+		assert.ErrorIs(t, err, ErrUnsupportedSignature)
+
+		err = RegisterService(gin.New(), svc, []Route{
+			{Method: http.MethodPost, Path: "/variadic", MethodName: "Variadic"},
+		})
+		assert.ErrorIs(t, err, ErrUnsupportedSignature)
+	})
+
+	t.Run("kind_mismatch", func(t *testing.T) {
+		r := gin.New()
+		err := RegisterService(r, svc, []Route{
+			{Method: http.MethodGet, Path: "/health", MethodName: "Health"},
+			{Method: http.MethodPost, Path: "/users", MethodName: "CreateUser", Kind: RouteKindConsumer},
+		})
+		assert.ErrorIs(t, err, ErrRouteKindMismatch)
+		assert.Empty(t, r.Routes())
+	})
+}
+
+// TestRegister tests registering a typed handler together with its method and path
+func TestRegister(t *testing.T) {
+	r := gin.New()
+	api := r.Group("/api")
+
+	Register(api, http.MethodGet, "/users/:id", func(ctx context.Context, req TestURIRequest) (TestResponse, error) {
+		return TestResponse{ID: req.ID, Name: "Alice"}, nil
+	}, WithSuccessStatus(http.StatusAccepted))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/7", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"id":7,"name":"Alice","email":""}`, w.Body.String())
+
+	routes := r.Routes()
+	assert.Len(t, routes, 1)
+	assert.Equal(t, http.MethodGet, routes[0].Method)
+	assert.Equal(t, "/api/users/:id", routes[0].Path)
+}
+
+// TestRegisterServiceOptions tests that route options affect decoding the same way as with WrapHandler
+func TestRegisterServiceOptions(t *testing.T) {
+	svc := &testService{}
+	options := []WrapHandlerOptionFunc{WithLenientNumbers(), WithQueryTag("q")}
+
+	r := gin.New()
+	r.POST("/wrapped", WrapHandler(svc.Search, options...))
+	err := RegisterService(r, svc, []Route{
+		{Method: http.MethodPost, Path: "/registered", MethodName: "Search", Options: options},
+		{Method: http.MethodPost, Path: "/upload", MethodName: "Upload"},
+	})
+	assert.NoError(t, err)
+
+	var bodies []string
+	for _, path := range []string{"/wrapped", "/registered"} {
+		req := httptest.NewRequest(http.MethodPost, path+"?filter=active", strings.NewReader(`{"id":42,"count":"3"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		bodies = append(bodies, w.Body.String())
+	}
+	assert.JSONEq(t, `{"id":"42","count":3,"Filter":"active"}`, bodies[0])
+	assert.JSONEq(t, bodies[0], bodies[1])
+
+	// []byte 参数接收原始请求体
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("raw payload"))
+	req.Header.Set("Content-Type", "application/octet-stream")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "11", w.Body.String())
+}
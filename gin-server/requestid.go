@@ -0,0 +1,49 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// RequestIDHeader 请求 ID 使用的请求头/响应头
+const RequestIDHeader = handler.DefaultRequestIDHeader
+
+// WithRequestID 为每个请求分配请求 ID（关联 ID）
+// 优先使用请求头 X-Request-ID 中客户端传入的值，没有时随机生成；请求 ID 会写入同名响应头并注入 ctx
+// 处理器通过 RequestIDFromContext 获取，默认错误处理器会在错误响应中以 trace_id 字段返回
+func WithRequestID() WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.requestID = true
+	}
+}
+
+// WithTracing 按 cfg 启用请求 ID 关联，与 restyclient.WithTracing 使用同一份配置即可让一个请求 ID 贯穿客户端和服务端
+// 请求 ID 从 cfg.Header 指定的请求头读取（没有时随机生成），写入同名响应头并注入 ctx；
+// 处理器中使用 ctx 调用配置了 WithTracing 的客户端时，请求 ID 会继续向下游传递。
+// cfg.Logger 非 nil 时输出访问日志，WithAccessLog 的日志同样以 cfg.LogKey 记录请求 ID
+func WithTracing(cfg handler.TracingConfig) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.requestID = true
+		opts.tracing = cfg
+		if cfg.Logger != nil {
+			WithAccessLog(cfg.Logger)(opts)
+		}
+	}
+}
+
+// RequestIDFromContext 返回 WithRequestID 注入的请求 ID，未启用时返回空字符串
+func RequestIDFromContext(ctx context.Context) string {
+	return handler.RequestIDFromContext(ctx)
+}
+
+// withRequestID 确定本次请求的请求 ID，写入 header 指定的响应头并返回携带请求 ID 的请求
+func withRequestID(w http.ResponseWriter, r *http.Request, header string) *http.Request {
+	id := r.Header.Get(header)
+	if id == "" {
+		id = handler.NewRequestID()
+	}
+	w.Header().Set(header, id)
+	return r.WithContext(handler.ContextWithRequestID(r.Context(), id))
+}
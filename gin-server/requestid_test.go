@@ -0,0 +1,152 @@
+package ginserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+	restyclient "github.com/zhangzqs/go-typed-rpc/resty-client"
+	"resty.dev/v3"
+)
+
+// TestWithRequestID tests assigning request IDs and exposing them in error responses
+func TestWithRequestID(t *testing.T) {
+	var seen string
+	r := gin.New()
+	r.GET("/users", WrapGetter(
+		func(ctx context.Context) (TestResponse, error) {
+			seen = RequestIDFromContext(ctx)
+			return TestResponse{Name: "ok"}, nil
+		},
+		WithRequestID(),
+	))
+	r.POST("/tasks", WrapAction(
+		func(ctx context.Context) error {
+			return errors.New("task failed")
+		},
+		WithRequestID(),
+	))
+
+	t.Run("generated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, seen, 32)
+		assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+		assert.NotContains(t, w.Body.String(), "trace_id")
+	})
+
+	t.Run("propagated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set(RequestIDHeader, "req-123")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, "req-123", seen)
+		assert.Equal(t, "req-123", w.Header().Get(RequestIDHeader))
+	})
+
+	t.Run("error_trace_id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/tasks", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var resp struct {
+			Error   string `json:"error"`
+			TraceID string `json:"trace_id"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "task failed", resp.Error)
+		assert.NotEmpty(t, resp.TraceID)
+		assert.Equal(t, w.Header().Get(RequestIDHeader), resp.TraceID)
+	})
+}
+
+// TestWithTracing tests that one request ID flows through client and server logs end to end
+func TestWithTracing(t *testing.T) {
+	var serverLogs, clientLogs bytes.Buffer
+	serverCfg := handler.TracingConfig{
+		Header: "X-Correlation-ID",
+		LogKey: "correlation_id",
+		Logger: slog.New(slog.NewJSONHandler(&serverLogs, nil)),
+	}
+	clientCfg := serverCfg
+	clientCfg.Logger = slog.New(slog.NewJSONHandler(&clientLogs, nil))
+
+	r := gin.New()
+	server := httptest.NewServer(r)
+	defer server.Close()
+	restyClient := resty.New().SetBaseURL(server.URL)
+
+	// /orders 在处理过程中使用同一个 ctx 调用下游的 /inventory
+	inventory := restyclient.NewGetter[TestResponse](restyClient, http.MethodGet, "/inventory", restyclient.WithTracing(clientCfg))
+	var seen []string
+	r.GET("/inventory", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		seen = append(seen, RequestIDFromContext(ctx))
+		return TestResponse{Name: "inventory"}, nil
+	}, WithTracing(serverCfg)))
+	r.GET("/orders", WrapGetter(func(ctx context.Context) (TestResponse, error) {
+		seen = append(seen, RequestIDFromContext(ctx))
+		return inventory(ctx)
+	}, WithTracing(serverCfg)))
+
+	orders := restyclient.NewGetter[TestResponse](restyClient, http.MethodGet, "/orders", restyclient.WithTracing(clientCfg))
+
+	readIDs := func(t *testing.T, buf *bytes.Buffer) []string {
+		var ids []string
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var record map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(line), &record))
+			id, _ := record["correlation_id"].(string)
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	t.Run("id_from_context", func(t *testing.T) {
+		serverLogs.Reset()
+		clientLogs.Reset()
+		seen = nil
+
+		ctx := handler.ContextWithRequestID(context.Background(), "req-e2e")
+		resp, err := orders(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "inventory", resp.Name)
+		assert.Equal(t, []string{"req-e2e", "req-e2e"}, seen)
+		assert.Equal(t, []string{"req-e2e", "req-e2e"}, readIDs(t, &clientLogs))
+		assert.Equal(t, []string{"req-e2e", "req-e2e"}, readIDs(t, &serverLogs))
+	})
+
+	t.Run("generated_by_client", func(t *testing.T) {
+		serverLogs.Reset()
+		clientLogs.Reset()
+		seen = nil
+
+		_, err := orders(context.Background())
+
+		assert.NoError(t, err)
+		clientIDs := readIDs(t, &clientLogs)
+		assert.Len(t, clientIDs, 2)
+		assert.Len(t, clientIDs[0], 32)
+		assert.Equal(t, []string{clientIDs[0], clientIDs[0]}, clientIDs)
+		assert.Equal(t, clientIDs, readIDs(t, &serverLogs))
+		assert.Equal(t, clientIDs, seen)
+	})
+}
@@ -0,0 +1,123 @@
+package ginserver
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// SchemaViolation 单条 JSON Schema 校验失败信息
+type SchemaViolation struct {
+	Path    string `json:"path"`    // 违反约束的 JSON Pointer 位置
+	Message string `json:"message"` // 失败原因
+}
+
+// SchemaValidationError 请求体未通过 JSON Schema 校验，默认错误处理器返回 422
+type SchemaValidationError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		path := v.Path
+		if path == "" {
+			path = "/"
+		}
+		msgs = append(msgs, path+": "+v.Message)
+	}
+	return "schema validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Details 将每条校验失败信息作为错误详情返回
+func (e *SchemaValidationError) Details() []handler.ErrorDetail {
+	details := make([]handler.ErrorDetail, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		path := v.Path
+		if path == "" {
+			path = "/"
+		}
+		details = append(details, handler.ErrorDetail{Field: path, Issue: v.Message})
+	}
+	return details
+}
+
+// WithJSONSchema 在绑定之前使用 JSON Schema 校验原始请求体
+// 请求体会被缓存，校验后仍可正常绑定；没有请求体或请求体不是 JSON（如表单）时跳过校验，请求体不是合法的 JSON 时返回 400
+// schema 无法编译时会 panic，应在注册路由时暴露问题
+func WithJSONSchema(schema []byte) WrapHandlerOptionFunc {
+	compiled, err := compileJSONSchema(schema)
+	if err != nil {
+		panic(fmt.Sprintf("ginserver: invalid json schema: %v", err))
+	}
+	return func(opts *WrapHandlerOptions) {
+		opts.jsonSchema = compiled
+	}
+}
+
+func compileJSONSchema(schema []byte) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, err
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("schema.json", doc); err != nil {
+		return nil, err
+	}
+	return c.Compile("schema.json")
+}
+
+// validateJSONSchema 读取并还原请求体，然后进行 JSON Schema 校验
+// 仅校验按 JSON 绑定的请求体，表单等其他格式的请求体跳过校验；sniffJSON 为 true 时 Content-Type 不明确但内容为 JSON 的请求体同样校验
+// 请求体不是合法的 JSON 时返回包装了 ErrMalformedRequest 的错误
+func validateJSONSchema(c *gin.Context, schema *jsonschema.Schema, sniffJSON bool) error {
+	if !hasRequestBody(c.Request) {
+		return nil
+	}
+	if !isJSONRequest(c) {
+		if !sniffJSON || !isAmbiguousContentType(c.ContentType()) {
+			return nil
+		}
+		if isJSON, err := sniffJSONBody(c); err != nil || !isJSON {
+			return err
+		}
+	}
+
+	body, err := peekRequestBody(c)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedRequest, err)
+	}
+
+	err = schema.Validate(inst)
+	if err == nil {
+		return nil
+	}
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+
+	var violations []SchemaViolation
+	for _, unit := range verr.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		violations = append(violations, SchemaViolation{
+			Path:    unit.InstanceLocation,
+			Message: unit.Error.String(),
+		})
+	}
+	return &SchemaValidationError{Violations: violations}
+}
@@ -0,0 +1,156 @@
+package ginserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// TestWithJSONSchema tests validating request bodies against a JSON Schema
+func TestWithJSONSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"required": ["name", "email"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2},
+			"email": {"type": "string"}
+		}
+	}`)
+
+	var captured error
+	r := gin.New()
+	r.POST("/users", WrapHandler(
+		func(ctx context.Context, req TestRequest) (TestRequest, error) {
+			return req, nil
+		},
+		WithJSONSchema(schema),
+		WithErrorHandler(func(c *gin.Context, err error) {
+			captured = err
+			DefaultErrorHandler()(c, err)
+		}),
+	))
+	r.GET("/users", WrapHandler(
+		func(ctx context.Context, req TestQueryRequest) (TestQueryRequest, error) {
+			return req, nil
+		},
+		WithJSONSchema(schema),
+	))
+
+	t.Run("valid_body_still_binds", func(t *testing.T) {
+		body := `{"name":"Alice","email":"alice@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"name":"Alice","email":"alice@example.com"}`, w.Body.String())
+	})
+
+	t.Run("violations", func(t *testing.T) {
+		captured = nil
+		body := `{"name":"A"}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var schemaErr *SchemaValidationError
+		assert.True(t, errors.As(captured, &schemaErr))
+		assert.Len(t, schemaErr.Violations, 2)
+
+		paths := []string{schemaErr.Violations[0].Path, schemaErr.Violations[1].Path}
+		assert.Contains(t, paths, "")
+		assert.Contains(t, paths, "/name")
+
+		// 校验失败信息同时作为错误详情返回
+		var resp struct {
+			Error struct {
+				Message string                `json:"message"`
+				Details []handler.ErrorDetail `json:"details"`
+			} `json:"error"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, schemaErr.Error(), resp.Error.Message)
+		assert.Equal(t, schemaErr.Details(), resp.Error.Details)
+	})
+
+	t.Run("no_body_skips_validation", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/users?page=1", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("malformed_json_is_bad_request", func(t *testing.T) {
+		captured = nil
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Alice"`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		// 与没有设置 schema 的路由一致
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.ErrorIs(t, captured, ErrMalformedRequest)
+	})
+
+	t.Run("non_json_body_skips_schema", func(t *testing.T) {
+		type FormRequest struct {
+			Name  string `form:"name" json:"name" binding:"required"`
+			Email string `form:"email" json:"email" binding:"required"`
+		}
+		fr := gin.New()
+		fr.POST("/users", WrapHandler(
+			func(ctx context.Context, req FormRequest) (FormRequest, error) {
+				return req, nil
+			},
+			WithJSONSchema(schema),
+		))
+
+		form := "name=Alice&email=alice@example.com"
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(form))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		fr.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"name":"Alice","email":"alice@example.com"}`, w.Body.String())
+
+		var buf strings.Builder
+		mw := multipart.NewWriter(&buf)
+		mw.WriteField("name", "Alice")
+		mw.WriteField("email", "alice@example.com")
+		mw.Close()
+		req = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(buf.String()))
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		w = httptest.NewRecorder()
+
+		fr.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"name":"Alice","email":"alice@example.com"}`, w.Body.String())
+	})
+
+	t.Run("invalid_schema_panics", func(t *testing.T) {
+		assert.Panics(t, func() {
+			WithJSONSchema([]byte(`{"type":`))
+		})
+	})
+}
@@ -0,0 +1,123 @@
+package ginserver
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type smartUploadRequest struct {
+	ID          int64                   `uri:"id" binding:"required"`
+	Title       string                  `json:"title" form:"title" binding:"required"`
+	Tags        []string                `json:"tags" form:"tags"`
+	Cover       *multipart.FileHeader   `json:"-" form:"cover"`
+	Attachments []*multipart.FileHeader `json:"-" form:"attachments"`
+}
+
+type smartUploadResponse struct {
+	ID          int64    `json:"id"`
+	Title       string   `json:"title"`
+	Tags        []string `json:"tags"`
+	Cover       string   `json:"cover"`
+	Attachments []string `json:"attachments"`
+}
+
+// TestSmartDecoder tests decoding JSON, urlencoded and multipart bodies into the same struct
+func TestSmartDecoder(t *testing.T) {
+	r := gin.New()
+	r.POST("/posts/:id", WrapHandler(func(ctx context.Context, req smartUploadRequest) (smartUploadResponse, error) {
+		resp := smartUploadResponse{ID: req.ID, Title: req.Title, Tags: req.Tags}
+		if req.Cover != nil {
+			f, err := req.Cover.Open()
+			if err != nil {
+				return resp, err
+			}
+			defer f.Close()
+			data, _ := io.ReadAll(f)
+			resp.Cover = req.Cover.Filename + ":" + string(data)
+		}
+		for _, a := range req.Attachments {
+			resp.Attachments = append(resp.Attachments, a.Filename)
+		}
+		return resp, nil
+	}, WithDecoder(SmartDecoder[smartUploadRequest]())))
+
+	serve := func(body io.Reader, contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/posts/7", body)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("json", func(t *testing.T) {
+		w := serve(strings.NewReader(`{"title":"hello","tags":["a","b"]}`), "application/json")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":7,"title":"hello","tags":["a","b"],"cover":"","attachments":null}`, w.Body.String())
+	})
+
+	t.Run("json_without_content_type", func(t *testing.T) {
+		w := serve(strings.NewReader(`{"title":"hello"}`), "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"title":"hello"`)
+	})
+
+	t.Run("urlencoded", func(t *testing.T) {
+		form := url.Values{"title": {"hello"}, "tags": {"a", "b"}}
+		w := serve(strings.NewReader(form.Encode()), "application/x-www-form-urlencoded")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":7,"title":"hello","tags":["a","b"],"cover":"","attachments":null}`, w.Body.String())
+	})
+
+	t.Run("multipart_with_files", func(t *testing.T) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		mw.WriteField("title", "hello")
+		mw.WriteField("tags", "a")
+		fw, _ := mw.CreateFormFile("cover", "cover.png")
+		fw.Write([]byte("png"))
+		fw, _ = mw.CreateFormFile("attachments", "a.txt")
+		fw.Write([]byte("a"))
+		fw, _ = mw.CreateFormFile("attachments", "b.txt")
+		fw.Write([]byte("b"))
+		mw.Close()
+
+		w := serve(&buf, mw.FormDataContentType())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":7,"title":"hello","tags":["a"],"cover":"cover.png:png","attachments":["a.txt","b.txt"]}`, w.Body.String())
+	})
+
+	t.Run("multipart_validation_after_uri", func(t *testing.T) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		mw.WriteField("tags", "a")
+		mw.Close()
+
+		w := serve(&buf, mw.FormDataContentType())
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "Title")
+		assert.NotContains(t, w.Body.String(), "'ID'")
+	})
+
+	t.Run("unsupported_media_type", func(t *testing.T) {
+		w := serve(strings.NewReader(`<post><title>hello</title></post>`), "application/xml")
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+}
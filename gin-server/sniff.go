@@ -0,0 +1,46 @@
+package ginserver
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sniffLimit 识别请求体类型时最多读取的字节数
+const sniffLimit = 512
+
+// WithContentTypeSniffing 在 Content-Type 缺失或不明确（text/plain、application/octet-stream）时识别请求体内容
+// 请求体第一个非空白字符为 { 或 [ 时按 JSON 绑定，避免客户端漏设 Content-Type 时被按表单绑定而得到空结构体
+// 仅对默认解码器生效
+func WithContentTypeSniffing() WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.contentTypeSniffing = true
+	}
+}
+
+// isAmbiguousContentType 判断 Content-Type 是否无法确定请求体格式
+func isAmbiguousContentType(contentType string) bool {
+	switch contentType {
+	case "", "text/plain", "application/octet-stream":
+		return true
+	}
+	return false
+}
+
+// sniffJSONBody 读取请求体开头判断是否为 JSON，读取的内容会还原供后续绑定使用
+func sniffJSONBody(c *gin.Context) (bool, error) {
+	head := make([]byte, sniffLimit)
+	n, err := io.ReadFull(c.Request.Body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	head = head[:n]
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), c.Request.Body), c.Request.Body}
+
+	trimmed := bytes.TrimLeft(head, " \t\r\n")
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '['), nil
+}
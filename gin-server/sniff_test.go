@@ -0,0 +1,72 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithContentTypeSniffing tests binding JSON bodies sent without a Content-Type
+func TestWithContentTypeSniffing(t *testing.T) {
+	type Item struct {
+		Name string `json:"name" form:"name"`
+	}
+
+	newRouter := func(options ...WrapHandlerOptionFunc) (*gin.Engine, *[]Item) {
+		var received []Item
+		r := gin.New()
+		r.POST("/items", WrapConsumer(func(ctx context.Context, req Item) error {
+			received = append(received, req)
+			return nil
+		}, options...))
+		r.POST("/batch", WrapConsumer(func(ctx context.Context, req []Item) error {
+			received = append(received, req...)
+			return nil
+		}, options...))
+		return r, &received
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		want        []Item
+	}{
+		{"missing_content_type", "/items", "", ` {"name":"Alice"}`, []Item{{Name: "Alice"}}},
+		{"text_plain", "/items", "text/plain", `{"name":"Bob"}`, []Item{{Name: "Bob"}}},
+		{"array", "/batch", "", "\n[{\"name\":\"A\"},{\"name\":\"B\"}]", []Item{{Name: "A"}, {Name: "B"}}},
+		{"form_body_unchanged", "/items", "application/x-www-form-urlencoded", `name=Carol`, []Item{{Name: "Carol"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, received := newRouter(WithContentTypeSniffing())
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, *received)
+		})
+	}
+
+	t.Run("disabled_by_default", func(t *testing.T) {
+		r, received := newRouter()
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"name":"Alice"}`))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []Item{{}}, *received)
+	})
+}
@@ -0,0 +1,108 @@
+package ginserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SSEContentType SSE 响应的 Content-Type
+const SSEContentType = "text/event-stream"
+
+// DefaultHeartbeatInterval 默认的 SSE 心跳间隔
+const DefaultHeartbeatInterval = 15 * time.Second
+
+// SubscribeFunc 订阅函数，返回的通道关闭表示订阅结束
+// ctx 在客户端断开或通道关闭后取消，订阅方应据此退订并释放资源
+type SubscribeFunc[O any] func(ctx context.Context) (<-chan O, error)
+
+// subscriptionOutput 订阅响应的内部输出类型
+type subscriptionOutput[O any] struct {
+	ch     <-chan O
+	cancel context.CancelFunc
+}
+
+// SubscriptionConfig WrapSubscription 的配置
+type SubscriptionConfig struct {
+	// Heartbeat 心跳间隔，定期写出 SSE 注释行，避免代理因连接空闲而断开
+	// 为 0 时使用 DefaultHeartbeatInterval，小于 0 表示不发送心跳
+	Heartbeat time.Duration
+}
+
+// WrapSubscription 包装订阅服务端事件通道的 SSE 处理器
+// 订阅成功后将通道中的每个元素编码为 JSON 作为一条 SSE 事件写出，客户端断开或通道关闭时取消 ctx 完成退订
+// 订阅函数返回的错误交给错误处理器；开始写出后客户端断开直接结束响应
+// 适用场景：实时看板、通知推送等需要持续接收服务端事件的场景
+func WrapSubscription[O any](
+	subscribe SubscribeFunc[O],
+	config SubscriptionConfig,
+	options ...WrapHandlerOptionFunc,
+) gin.HandlerFunc {
+	heartbeat := config.Heartbeat
+	if heartbeat == 0 {
+		heartbeat = DefaultHeartbeatInterval
+	}
+
+	return WrapHandler(func(ctx context.Context, _ struct{}) (subscriptionOutput[O], error) {
+		ctx, cancel := context.WithCancel(ctx)
+		ch, err := subscribe(ctx)
+		if err != nil {
+			cancel()
+			return subscriptionOutput[O]{}, err
+		}
+		return subscriptionOutput[O]{ch: ch, cancel: cancel}, nil
+	}, append([]WrapHandlerOptionFunc{WithEncoder(sseEncoder[O](heartbeat))}, options...)...)
+}
+
+// sseEncoder 将订阅通道中的元素逐条写出为 SSE 事件，并按 heartbeat 间隔写出心跳
+func sseEncoder[O any](heartbeat time.Duration) EncoderFunc {
+	return func(c *gin.Context, output any) error {
+		out, ok := output.(subscriptionOutput[O])
+		if !ok {
+			return ErrEncoderReceivedWrongType
+		}
+		defer out.cancel()
+
+		c.Header("Content-Type", SSEContentType)
+		c.Header("Cache-Control", "no-cache")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		var tick <-chan time.Time
+		if heartbeat > 0 {
+			ticker := time.NewTicker(heartbeat)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		done := c.Request.Context().Done()
+		for {
+			select {
+			case item, ok := <-out.ch:
+				if !ok {
+					return nil
+				}
+				data, err := json.Marshal(item)
+				if err != nil {
+					// 已开始写入响应，无法再返回错误状态码，直接终止
+					c.Abort()
+					return nil
+				}
+				if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", data); err != nil {
+					return nil
+				}
+			case <-tick:
+				if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+					return nil
+				}
+			case <-done:
+				return nil
+			}
+			c.Writer.Flush()
+		}
+	}
+}
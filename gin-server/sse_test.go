@@ -0,0 +1,90 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrapSubscription tests streaming a subscription channel as SSE events
+func TestWrapSubscription(t *testing.T) {
+	t.Run("channel_closed", func(t *testing.T) {
+		unsubscribed := make(chan struct{})
+		r := gin.New()
+		r.GET("/events", WrapSubscription(func(ctx context.Context) (<-chan TestResponse, error) {
+			ch := make(chan TestResponse, 2)
+			ch <- TestResponse{ID: 1, Name: "Alice"}
+			ch <- TestResponse{ID: 2, Name: "Bob"}
+			close(ch)
+			go func() {
+				<-ctx.Done()
+				close(unsubscribed)
+			}()
+			return ch, nil
+		}, SubscriptionConfig{}))
+
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, SSEContentType, w.Header().Get("Content-Type"))
+		assert.Equal(t, "data: {\"id\":1,\"name\":\"Alice\",\"email\":\"\"}\n\n"+
+			"data: {\"id\":2,\"name\":\"Bob\",\"email\":\"\"}\n\n", w.Body.String())
+		select {
+		case <-unsubscribed:
+		case <-time.After(time.Second):
+			t.Fatal("subscription context not canceled")
+		}
+	})
+
+	t.Run("client_disconnect_with_heartbeat", func(t *testing.T) {
+		unsubscribed := make(chan struct{})
+		r := gin.New()
+		r.GET("/events", WrapSubscription(func(ctx context.Context) (<-chan TestResponse, error) {
+			go func() {
+				<-ctx.Done()
+				close(unsubscribed)
+			}()
+			return make(chan TestResponse), nil
+		}, SubscriptionConfig{Heartbeat: 10 * time.Millisecond}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, strings.HasPrefix(w.Body.String(), ": heartbeat\n\n"))
+		select {
+		case <-unsubscribed:
+		case <-time.After(time.Second):
+			t.Fatal("subscription context not canceled")
+		}
+	})
+
+	t.Run("subscribe_error", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/events", WrapSubscription(func(ctx context.Context) (<-chan TestResponse, error) {
+			return nil, errors.New("subscribe failed")
+		}, SubscriptionConfig{}))
+
+		req := httptest.NewRequest(http.MethodGet, "/events", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), "subscribe failed")
+	})
+}
@@ -0,0 +1,97 @@
+package ginserver
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/codec/json"
+)
+
+const defaultMultipartMemory = 32 << 20
+
+// WithValidator 为单个处理器设置独立的结构体校验器，不修改 gin 全局的 binding.Validator
+// 校验器接口与 binding.StructValidator 一致，可直接复用基于 go-playground/validator 的自定义规则
+// 设置后默认解码器在绑定时跳过全局校验，绑定完成后使用该校验器校验输入结构体
+// URI、Query、JSON、XML 和表单请求体均支持；其余类型的请求体仍由 gin 绑定并触发全局校验
+func WithValidator(v binding.StructValidator) WrapHandlerOptionFunc {
+	return func(opts *WrapHandlerOptions) {
+		opts.validator = v
+	}
+}
+
+// unvalidatedBinders 返回与 c.ShouldBindUri/ShouldBindWith/ShouldBindQuery 对应但不触发全局校验的绑定函数
+// uriTag、queryTag 为路径参数和查询参数使用的标签名
+func unvalidatedBinders(c *gin.Context, uriTag, queryTag string) (
+	bindURI func(obj any) error,
+	bindBody func(obj any, b binding.Binding) error,
+	bindQuery func(obj any) error,
+) {
+	bindURI = func(obj any) error {
+		m := make(map[string][]string, len(c.Params))
+		for _, p := range c.Params {
+			m[p.Key] = []string{p.Value}
+		}
+		return binding.MapFormWithTag(obj, m, uriTag)
+	}
+	bindBody = func(obj any, b binding.Binding) error {
+		return bindBodyWithoutValidation(c, obj, b)
+	}
+	bindQuery = func(obj any) error {
+		return binding.MapFormWithTag(obj, c.Request.URL.Query(), queryTag)
+	}
+	return bindURI, bindBody, bindQuery
+}
+
+// bindBodyWithoutValidation 按 b 的格式绑定请求体，不触发全局校验
+// 解码行为与 gin 对应的 Binding 一致，不支持的格式回退到 c.ShouldBindWith
+func bindBodyWithoutValidation(c *gin.Context, ptr any, b binding.Binding) error {
+	req := c.Request
+	if cb, ok := b.(codecJSONBinding); ok {
+		return cb.decode(req, ptr)
+	}
+	switch b {
+	case binding.JSON:
+		decoder := json.API.NewDecoder(req.Body)
+		if binding.EnableDecoderUseNumber {
+			decoder.UseNumber()
+		}
+		if binding.EnableDecoderDisallowUnknownFields {
+			decoder.DisallowUnknownFields()
+		}
+		return decoder.Decode(ptr)
+	case binding.XML:
+		return xml.NewDecoder(req.Body).Decode(ptr)
+	case binding.Form:
+		if err := req.ParseForm(); err != nil {
+			return err
+		}
+		if err := req.ParseMultipartForm(defaultMultipartMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return err
+		}
+		// req.Form 同时包含查询参数，请求体来源只读取 PostForm，查询参数由 BindSourceQuery 按 queryTag 绑定
+		return binding.MapFormWithTag(ptr, req.PostForm, "form")
+	case binding.FormMultipart:
+		if err := req.ParseMultipartForm(defaultMultipartMemory); err != nil {
+			return err
+		}
+		if err := binding.MapFormWithTag(ptr, req.MultipartForm.Value, "form"); err != nil {
+			return err
+		}
+		bindMultipartFiles(ptr, req.MultipartForm.File)
+		return nil
+	default:
+		return c.ShouldBindWith(ptr, b)
+	}
+}
+
+// validateStruct 使用自定义校验器校验绑定结果，仅校验结构体（或结构体指针）
+func validateStruct(v binding.StructValidator, ptr any) error {
+	if !isStructType(reflect.TypeOf(ptr)) {
+		return nil
+	}
+	return v.ValidateStruct(ptr)
+}
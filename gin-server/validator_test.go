@@ -0,0 +1,101 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+// testValidator 基于独立 validator 实例的 binding.StructValidator 实现
+type testValidator struct {
+	validate *validator.Validate
+}
+
+func (v *testValidator) ValidateStruct(obj any) error {
+	return v.validate.Struct(obj)
+}
+
+func (v *testValidator) Engine() any {
+	return v.validate
+}
+
+func newPhoneValidator() *testValidator {
+	phone := regexp.MustCompile(`^1\d{10}$`)
+	validate := validator.New()
+	validate.SetTagName("binding")
+	validate.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		return phone.MatchString(fl.Field().String())
+	})
+	return &testValidator{validate: validate}
+}
+
+// TestWithValidator tests per-handler validators with custom rules
+func TestWithValidator(t *testing.T) {
+	type contactRequest struct {
+		ID    int64  `uri:"id" binding:"required"`
+		Phone string `json:"phone" form:"phone" binding:"required,phone"`
+	}
+
+	r := gin.New()
+	r.POST("/contacts/:id", WrapHandler(
+		func(ctx context.Context, req contactRequest) (contactRequest, error) {
+			return req, nil
+		},
+		WithValidator(newPhoneValidator()),
+	))
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		valid       bool
+	}{
+		{name: "json_valid", contentType: "application/json", body: `{"phone":"13800138000"}`, valid: true},
+		{name: "json_invalid", contentType: "application/json", body: `{"phone":"12345"}`, valid: false},
+		{name: "form_valid", contentType: "application/x-www-form-urlencoded", body: "phone=13800138000", valid: true},
+		{name: "form_missing", contentType: "application/x-www-form-urlencoded", body: "name=alice", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/contacts/1", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			if tt.valid {
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.JSONEq(t, `{"ID":1,"phone":"13800138000"}`, w.Body.String())
+			} else {
+				assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+				assert.Contains(t, w.Body.String(), "Phone")
+			}
+		})
+	}
+
+	t.Run("global_validator_untouched", func(t *testing.T) {
+		r := gin.New()
+		r.POST("/contacts/:id", WrapHandler(
+			func(ctx context.Context, req TestRequest) (TestRequest, error) {
+				return req, nil
+			},
+		))
+
+		req := httptest.NewRequest(http.MethodPost, "/contacts/1", strings.NewReader(`{"name":"Alice"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "Email")
+	})
+}
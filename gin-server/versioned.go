@@ -0,0 +1,85 @@
+package ginserver
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VersionHeader 指定 API 版本的请求头
+const VersionHeader = "X-API-Version"
+
+// ErrUnsupportedVersion 请求的 API 版本不存在，默认错误处理器返回 406
+var ErrUnsupportedVersion = errors.New("unsupported api version")
+
+// VersionedHandler 按版本分发的处理器
+// 同一路径下可注册多个版本，每个版本使用各自的 WrapHandler 包装（拥有独立的 I/O 类型）
+// 版本从 X-API-Version 请求头或 Accept 的 version 参数（如 application/json; version=2）中读取
+type VersionedHandler struct {
+	handlers       map[string]gin.HandlerFunc
+	defaultVersion string
+	errorHandler   ErrorHandlerFunc
+}
+
+// NewVersionedHandler 创建按版本分发的处理器
+func NewVersionedHandler() *VersionedHandler {
+	return &VersionedHandler{
+		handlers:     make(map[string]gin.HandlerFunc),
+		errorHandler: DefaultErrorHandler(),
+	}
+}
+
+// Version 注册指定版本的处理器
+func (v *VersionedHandler) Version(version string, h gin.HandlerFunc) *VersionedHandler {
+	v.handlers[version] = h
+	return v
+}
+
+// Default 设置未指定版本时使用的版本
+func (v *VersionedHandler) Default(version string) *VersionedHandler {
+	v.defaultVersion = version
+	return v
+}
+
+// ErrorHandler 设置版本不存在时使用的错误处理器
+func (v *VersionedHandler) ErrorHandler(errHandler ErrorHandlerFunc) *VersionedHandler {
+	v.errorHandler = errHandler
+	return v
+}
+
+// Handler 返回可注册到路由的 gin.HandlerFunc
+func (v *VersionedHandler) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := requestedVersion(c)
+		if version == "" {
+			version = v.defaultVersion
+		}
+
+		h, ok := v.handlers[version]
+		if !ok {
+			v.errorHandler(c, fmt.Errorf("%w: %q", ErrUnsupportedVersion, version))
+			return
+		}
+		h(c)
+	}
+}
+
+// requestedVersion 读取请求中指定的 API 版本，请求头优先
+func requestedVersion(c *gin.Context) string {
+	if version := strings.TrimSpace(c.GetHeader(VersionHeader)); version != "" {
+		return version
+	}
+	for _, accept := range strings.Split(c.GetHeader("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		if version := params["version"]; version != "" {
+			return version
+		}
+	}
+	return ""
+}
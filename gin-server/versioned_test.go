@@ -0,0 +1,72 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestVersionedHandler tests dispatching to handlers by API version
+func TestVersionedHandler(t *testing.T) {
+	type UserV1 struct {
+		Name string `json:"name"`
+	}
+
+	type UserV2 struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	}
+
+	r := gin.New()
+	r.GET("/users/:id", NewVersionedHandler().
+		Version("1", WrapHandler(
+			func(ctx context.Context, req TestURIRequest) (UserV1, error) {
+				return UserV1{Name: "Alice Smith"}, nil
+			},
+		)).
+		Version("2", WrapHandler(
+			func(ctx context.Context, req TestURIRequest) (UserV2, error) {
+				return UserV2{FirstName: "Alice", LastName: "Smith"}, nil
+			},
+		)).
+		Default("1").
+		Handler(),
+	)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		status int
+		body   string
+	}{
+		{"header_v1", map[string]string{VersionHeader: "1"}, http.StatusOK, `{"name":"Alice Smith"}`},
+		{"header_v2", map[string]string{VersionHeader: "2"}, http.StatusOK, `{"first_name":"Alice","last_name":"Smith"}`},
+		{"accept_param", map[string]string{"Accept": "application/json; version=2"}, http.StatusOK, `{"first_name":"Alice","last_name":"Smith"}`},
+		{"default", nil, http.StatusOK, `{"name":"Alice Smith"}`},
+		{"unknown", map[string]string{VersionHeader: "3"}, http.StatusNotAcceptable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.body != "" {
+				assert.JSONEq(t, tt.body, w.Body.String())
+			} else {
+				assert.True(t, strings.Contains(w.Body.String(), ErrUnsupportedVersion.Error()))
+			}
+		})
+	}
+}
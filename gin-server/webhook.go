@@ -0,0 +1,87 @@
+package ginserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	restyclient "github.com/zhangzqs/go-typed-rpc/resty-client"
+	"resty.dev/v3"
+)
+
+// DefaultValidationWebhookTimeout 校验 Webhook 请求的默认超时时间
+const DefaultValidationWebhookTimeout = 5 * time.Second
+
+// ValidationRejectedError 校验 Webhook 拒绝请求时返回的错误，默认错误处理器使用 Webhook 返回的状态码
+type ValidationRejectedError struct {
+	Status  int    // Webhook 返回的状态码
+	Message string // Webhook 返回的拒绝原因
+}
+
+func (e *ValidationRejectedError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("request rejected by validation webhook: %d", e.Status)
+	}
+	return e.Message
+}
+
+// WithValidationWebhook 在处理器执行前将解码后的输入以 JSON POST 到外部校验服务
+// 校验服务返回 2xx 表示通过；返回其他状态码表示拒绝，此时返回 *ValidationRejectedError，
+// 响应状态码与校验服务一致，拒绝原因取自响应体的 error 或 message 字段，不是 JSON 时使用响应体文本
+// 校验服务不可达或超时（DefaultValidationWebhookTimeout）时返回错误，请求不会被放行
+// 适用场景：由集中的策略服务统一审核请求参数
+func WithValidationWebhook(url string) WrapHandlerOptionFunc {
+	client := resty.New().SetTimeout(DefaultValidationWebhookTimeout)
+	validate := restyclient.NewConsumer[any](client, http.MethodPost, url,
+		// 输入结构体上的 form、header 等服务端标签对校验服务没有意义，整体序列化为请求体
+		restyclient.WithEncoder(func(req *resty.Request, input any) error {
+			req.SetBody(input)
+			return nil
+		}),
+		// 通过时忽略响应体
+		restyclient.WithDecoder(func(resp *resty.Response) (any, error) {
+			return struct{}{}, nil
+		}),
+		restyclient.WithErrorHandler(webhookErrorHandler),
+	)
+	return func(opts *WrapHandlerOptions) {
+		opts.validationWebhook = validate
+	}
+}
+
+// webhookErrorHandler 将校验服务的非 2xx 响应转换为 *ValidationRejectedError
+func webhookErrorHandler(resp *resty.Response, err error) error {
+	if err != nil {
+		return fmt.Errorf("validation webhook: %w", restyclient.ClassifyError(err))
+	}
+	if !resp.IsError() {
+		return nil
+	}
+	return &ValidationRejectedError{Status: resp.StatusCode(), Message: webhookMessage(resp.Bytes())}
+}
+
+// webhookMessage 从校验服务的响应体中提取拒绝原因
+func webhookMessage(body []byte) string {
+	var payload struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if payload.Error != "" {
+			return payload.Error
+		}
+		return payload.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// runValidationWebhook 执行校验 Webhook，未设置时直接放行
+func runValidationWebhook(ctx context.Context, validate func(ctx context.Context, input any) error, input any) error {
+	if validate == nil {
+		return nil
+	}
+	return validate(ctx, input)
+}
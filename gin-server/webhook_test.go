@@ -0,0 +1,102 @@
+package ginserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithValidationWebhook tests approving and rejecting requests through an external validation service
+func TestWithValidationWebhook(t *testing.T) {
+	policy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TestRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case strings.HasSuffix(req.Email, "@blocked.example"):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"email domain is blocked"}`))
+		case req.Name == "admin":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("reserved name"))
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer policy.Close()
+
+	called := false
+	newRouter := func(url string) *gin.Engine {
+		r := gin.New()
+		r.POST("/users", WrapHandler(
+			func(ctx context.Context, req TestRequest) (TestResponse, error) {
+				called = true
+				return TestResponse{ID: 1, Name: req.Name, Email: req.Email}, nil
+			},
+			WithValidationWebhook(url),
+		))
+		return r
+	}
+
+	serve := func(r *gin.Engine, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	r := newRouter(policy.URL)
+
+	t.Run("approved", func(t *testing.T) {
+		called = false
+		w := serve(r, `{"name":"Alice","email":"alice@example.com"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, called)
+	})
+
+	t.Run("rejected_json", func(t *testing.T) {
+		called = false
+		w := serve(r, `{"name":"Bob","email":"bob@blocked.example"}`)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.JSONEq(t, `{"error":"email domain is blocked"}`, w.Body.String())
+		assert.False(t, called)
+	})
+
+	t.Run("rejected_text", func(t *testing.T) {
+		w := serve(r, `{"name":"admin","email":"admin@example.com"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{"error":"reserved name"}`, w.Body.String())
+	})
+
+	t.Run("webhook_unreachable", func(t *testing.T) {
+		called = false
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+
+		w := serve(newRouter(down.URL), `{"name":"Alice","email":"alice@example.com"}`)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.False(t, called)
+	})
+}
+
+// TestValidationRejectedError tests the error message and status mapping
+func TestValidationRejectedError(t *testing.T) {
+	err := error(&ValidationRejectedError{Status: http.StatusForbidden})
+
+	assert.Equal(t, "request rejected by validation webhook: 403", err.Error())
+	var rejected *ValidationRejectedError
+	assert.True(t, errors.As(err, &rejected))
+	assert.Equal(t, http.StatusForbidden, StatusFromError(err))
+}
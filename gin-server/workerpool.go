@@ -0,0 +1,53 @@
+package ginserver
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// workerPool 全局处理器执行池，slots 的容量即最大并发数
+type workerPool struct {
+	slots chan struct{}
+}
+
+var globalWorkerPool atomic.Pointer[workerPool]
+
+// SetWorkerPool 设置所有包装处理器共享的执行池大小
+// 设置后同一时刻最多 size 个处理器在执行，其余请求在 HTTP 协程中排队等待空闲位置
+// 与按路由设置的 WithRateLimit 不同，执行池限制的是全部路由的总并发，用于在流量突增时保护共享资源
+// 等待期间请求被取消时返回 ctx.Err()；size <= 0 表示取消限制
+// 重新设置时，已经在执行的处理器仍占用旧执行池的位置，新请求使用新的执行池
+func SetWorkerPool(size int) {
+	if size <= 0 {
+		globalWorkerPool.Store(nil)
+		return
+	}
+	globalWorkerPool.Store(&workerPool{slots: make(chan struct{}, size)})
+}
+
+// acquireWorker 获取执行位置，返回的函数用于释放；未设置执行池时直接返回
+func acquireWorker(ctx context.Context) (func(), error) {
+	pool := globalWorkerPool.Load()
+	if pool == nil {
+		return func() {}, nil
+	}
+	select {
+	case pool.slots <- struct{}{}:
+		return func() { <-pool.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runWithWorker 占用执行池中的位置执行处理器，处理器 panic 时同样会释放位置
+func runWithWorker[I, O any](c *gin.Context, args I, h func(c *gin.Context, args I) (O, error)) (O, error) {
+	release, err := acquireWorker(c.Request.Context())
+	if err != nil {
+		var zero O
+		return zero, err
+	}
+	defer release()
+	return h(c, args)
+}
@@ -0,0 +1,93 @@
+package ginserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetWorkerPool tests that the shared worker pool caps concurrency across routes
+func TestSetWorkerPool(t *testing.T) {
+	SetWorkerPool(1)
+	t.Cleanup(func() { SetWorkerPool(0) })
+
+	var running, maxRunning atomic.Int32
+	slow := func(ctx context.Context) (TestResponse, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		return TestResponse{Name: "ok"}, nil
+	}
+
+	r := gin.New()
+	r.GET("/a", WrapGetter(slow))
+	r.GET("/b", WrapGetter(slow))
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, path := range []string{"/a", "/b"} {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+		}(path)
+	}
+	wg.Wait()
+
+	// 两个请求串行执行
+	assert.Equal(t, int32(1), maxRunning.Load())
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+// TestSetWorkerPoolCanceled tests that waiting requests give up when their context is canceled
+func TestSetWorkerPoolCanceled(t *testing.T) {
+	SetWorkerPool(1)
+	t.Cleanup(func() { SetWorkerPool(0) })
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	r := gin.New()
+	r.GET("/block", WrapAction(func(ctx context.Context) error {
+		close(started)
+		<-unblock
+		return nil
+	}))
+	r.GET("/wait", WrapAction(func(ctx context.Context) error {
+		t.Error("handler should not run")
+		return nil
+	}))
+
+	go func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/block", nil))
+	}()
+	<-started
+	defer close(unblock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/wait", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), context.DeadlineExceeded.Error())
+}
@@ -0,0 +1,179 @@
+package ginwebsocket
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// HandlerFunc WebSocket 处理器
+// recv 接收客户端发来并解码为 I 的消息，连接断开时关闭；写入 send 的 O 会编码为 JSON 帧发送给客户端
+// 关闭 send 或处理器返回都会关闭连接；客户端断开时 ctx 会被取消
+type HandlerFunc[I, O any] func(ctx context.Context, recv <-chan I, send chan<- O) error
+
+type WrapWebSocketOptions struct {
+	recvBuffer   int
+	sendBuffer   int
+	readLimit    int64
+	closeTimeout time.Duration
+}
+
+type WrapWebSocketOptionFunc func(*WrapWebSocketOptions)
+
+// WithRecvBuffer 设置 recv 通道的缓冲大小
+func WithRecvBuffer(n int) WrapWebSocketOptionFunc {
+	return func(opts *WrapWebSocketOptions) {
+		opts.recvBuffer = n
+	}
+}
+
+// WithSendBuffer 设置 send 通道的缓冲大小
+func WithSendBuffer(n int) WrapWebSocketOptionFunc {
+	return func(opts *WrapWebSocketOptions) {
+		opts.sendBuffer = n
+	}
+}
+
+// WithReadLimit 设置单条消息的最大字节数，超出时关闭连接
+func WithReadLimit(limit int64) WrapWebSocketOptionFunc {
+	return func(opts *WrapWebSocketOptions) {
+		opts.readLimit = limit
+	}
+}
+
+func mergeOptions(options ...WrapWebSocketOptionFunc) *WrapWebSocketOptions {
+	opts := WrapWebSocketOptions{
+		closeTimeout: time.Second,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return &opts
+}
+
+// WrapWebSocket 包装双向类型化消息的 WebSocket 处理器
+// 升级连接后，将客户端的 JSON 帧解码为 I，将处理器输出的 O 编码为 JSON 帧
+func WrapWebSocket[I, O any](
+	h HandlerFunc[I, O],
+	upgrader websocket.Upgrader,
+	options ...WrapWebSocketOptionFunc,
+) gin.HandlerFunc {
+	opts := mergeOptions(options...)
+
+	return func(c *gin.Context) {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// Upgrade 失败时已写入 HTTP 错误响应
+			return
+		}
+		defer conn.Close()
+		if opts.readLimit > 0 {
+			conn.SetReadLimit(opts.readLimit)
+		}
+
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+
+		var closeOnce sync.Once
+		closeConn := func(code int, text string) {
+			text = truncateCloseReason(text)
+			closeOnce.Do(func() {
+				msg := websocket.FormatCloseMessage(code, text)
+				conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(opts.closeTimeout))
+			})
+		}
+
+		recv := make(chan I, opts.recvBuffer)
+		send := make(chan O, opts.sendBuffer)
+		handlerDone := make(chan struct{})
+		writerDone := make(chan struct{})
+
+		// 读取客户端消息，连接断开或解码失败时取消 ctx
+		go func() {
+			defer close(recv)
+			defer cancel()
+			for {
+				var in I
+				if err := conn.ReadJSON(&in); err != nil {
+					return
+				}
+				select {
+				case recv <- in:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		// 向客户端写出处理器输出的消息
+		go func() {
+			defer close(writerDone)
+			for {
+				select {
+				case out, ok := <-send:
+					if !ok {
+						closeConn(websocket.CloseNormalClosure, "")
+						cancel()
+						return
+					}
+					if err := conn.WriteJSON(out); err != nil {
+						cancel()
+						return
+					}
+				case <-handlerDone:
+					drainSend(conn, send)
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		err = h(ctx, recv, send)
+		close(handlerDone)
+		<-writerDone
+
+		if err != nil {
+			closeConn(websocket.CloseInternalServerErr, err.Error())
+			return
+		}
+		closeConn(websocket.CloseNormalClosure, "")
+	}
+}
+
+// maxCloseReasonLen 关闭帧中原因文本的最大字节数（控制帧载荷 125 字节减去 2 字节的关闭码）
+const maxCloseReasonLen = 123
+
+// truncateCloseReason 将关闭原因截断到 maxCloseReasonLen 字节以内，截断位置退回到字符边界，
+// 避免切断多字节字符产生非法 UTF-8，导致对端按协议错误处理关闭帧
+func truncateCloseReason(text string) string {
+	if len(text) <= maxCloseReasonLen {
+		return text
+	}
+	i := maxCloseReasonLen
+	for i > 0 && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return text[:i]
+}
+
+// drainSend 处理器返回后写出 send 中已缓冲的消息
+func drainSend[O any](conn *websocket.Conn, send <-chan O) {
+	for {
+		select {
+		case out, ok := <-send:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(out); err != nil {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
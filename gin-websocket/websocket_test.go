@@ -0,0 +1,160 @@
+package ginwebsocket
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+type ChatRequest struct {
+	Text string `json:"text"`
+}
+
+type ChatResponse struct {
+	Echo  string `json:"echo"`
+	Count int    `json:"count"`
+}
+
+func dial(t *testing.T, server *httptest.Server) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	return conn
+}
+
+// TestWrapWebSocket tests exchanging typed messages over a WebSocket connection
+func TestWrapWebSocket(t *testing.T) {
+	t.Run("echo_and_client_disconnect", func(t *testing.T) {
+		cancelled := make(chan struct{})
+
+		r := gin.New()
+		r.GET("/ws", WrapWebSocket(
+			func(ctx context.Context, recv <-chan ChatRequest, send chan<- ChatResponse) error {
+				count := 0
+				for {
+					select {
+					case in, ok := <-recv:
+						if !ok {
+							<-ctx.Done()
+							close(cancelled)
+							return nil
+						}
+						count++
+						send <- ChatResponse{Echo: in.Text, Count: count}
+					case <-ctx.Done():
+						close(cancelled)
+						return nil
+					}
+				}
+			},
+			websocket.Upgrader{},
+		))
+		server := httptest.NewServer(r)
+		defer server.Close()
+
+		conn := dial(t, server)
+
+		for i, text := range []string{"hello", "world"} {
+			assert.NoError(t, conn.WriteJSON(ChatRequest{Text: text}))
+
+			var resp ChatResponse
+			assert.NoError(t, conn.ReadJSON(&resp))
+			assert.Equal(t, text, resp.Echo)
+			assert.Equal(t, i+1, resp.Count)
+		}
+
+		conn.Close()
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Fatal("context was not cancelled after client disconnect")
+		}
+	})
+
+	t.Run("closing_send_closes_socket", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/ws", WrapWebSocket(
+			func(ctx context.Context, recv <-chan ChatRequest, send chan<- ChatResponse) error {
+				send <- ChatResponse{Echo: "bye"}
+				close(send)
+				<-ctx.Done()
+				return nil
+			},
+			websocket.Upgrader{},
+		))
+		server := httptest.NewServer(r)
+		defer server.Close()
+
+		conn := dial(t, server)
+		defer conn.Close()
+
+		var resp ChatResponse
+		assert.NoError(t, conn.ReadJSON(&resp))
+		assert.Equal(t, "bye", resp.Echo)
+
+		_, _, err := conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+	})
+
+	t.Run("handler_error_closes_socket", func(t *testing.T) {
+		r := gin.New()
+		r.GET("/ws", WrapWebSocket(
+			func(ctx context.Context, recv <-chan ChatRequest, send chan<- ChatResponse) error {
+				<-recv
+				return errors.New("unauthorized topic")
+			},
+			websocket.Upgrader{},
+		))
+		server := httptest.NewServer(r)
+		defer server.Close()
+
+		conn := dial(t, server)
+		defer conn.Close()
+
+		assert.NoError(t, conn.WriteJSON(ChatRequest{Text: "subscribe"}))
+
+		_, _, err := conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		assert.True(t, errors.As(err, &closeErr))
+		assert.Equal(t, websocket.CloseInternalServerErr, closeErr.Code)
+		assert.Equal(t, "unauthorized topic", closeErr.Text)
+	})
+
+	t.Run("long_close_reason_keeps_utf8", func(t *testing.T) {
+		// 151 字节，第 123 字节落在多字节字符中间
+		reason := "x" + strings.Repeat("错", 50)
+		r := gin.New()
+		r.GET("/ws", WrapWebSocket(
+			func(ctx context.Context, recv <-chan ChatRequest, send chan<- ChatResponse) error {
+				<-recv
+				return errors.New(reason)
+			},
+			websocket.Upgrader{},
+		))
+		server := httptest.NewServer(r)
+		defer server.Close()
+
+		conn := dial(t, server)
+		defer conn.Close()
+
+		assert.NoError(t, conn.WriteJSON(ChatRequest{Text: "subscribe"}))
+
+		_, _, err := conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		assert.True(t, errors.As(err, &closeErr))
+		assert.Equal(t, websocket.CloseInternalServerErr, closeErr.Code)
+		assert.Equal(t, "x"+strings.Repeat("错", 40), closeErr.Text)
+	})
+}
@@ -0,0 +1,229 @@
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/zhangzqs/go-typed-rpc/handler"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type RequestEncoderFunc func(input any) ([]byte, error)
+
+type ResponseDecoderFunc func(data []byte) (any, error)
+
+type ErrorHandlerFunc func(err error) error
+
+// 错误定义
+var ErrDecoderReturnedWrongType = errors.New("decoder returned wrong type")
+
+type ClientOptions struct {
+	encoder      RequestEncoderFunc
+	decoder      ResponseDecoderFunc
+	errorHandler ErrorHandlerFunc
+	callOptions  []grpc.CallOption
+}
+
+type ClientOptionFunc func(*ClientOptions)
+
+func WithEncoder(encoder RequestEncoderFunc) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.encoder = encoder
+	}
+}
+
+func WithDecoder(decoder ResponseDecoderFunc) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.decoder = decoder
+	}
+}
+
+func WithErrorHandler(errHandler ErrorHandlerFunc) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.errorHandler = errHandler
+	}
+}
+
+// WithCallOptions 追加每次调用使用的 grpc.CallOption，如元数据、压缩等
+func WithCallOptions(callOptions ...grpc.CallOption) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.callOptions = append(opts.callOptions, callOptions...)
+	}
+}
+
+// DefaultRequestEncoder 默认请求编码器
+// 将输入序列化为 JSON 作为请求消息
+func DefaultRequestEncoder[I any]() RequestEncoderFunc {
+	return func(input any) ([]byte, error) {
+		return json.Marshal(input)
+	}
+}
+
+// DefaultResponseDecoder 默认响应解码器
+// 将响应消息反序列化为目标类型，空消息返回零值
+func DefaultResponseDecoder[O any]() ResponseDecoderFunc {
+	return func(data []byte) (any, error) {
+		var result O
+		if len(data) == 0 {
+			return result, nil
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
+
+// DefaultErrorHandler 默认错误处理器
+// 将 gRPC 状态码映射为 handler 包中的标准错误，context 取消和超时映射为 context.Canceled/context.DeadlineExceeded
+// 业务层可继续使用 errors.Is 判断错误类型，与 HTTP 传输保持一致
+func DefaultErrorHandler() ErrorHandlerFunc {
+	return func(err error) error {
+		if err == nil {
+			return nil
+		}
+		st, ok := status.FromError(err)
+		if !ok {
+			return err
+		}
+
+		var target error
+		switch st.Code() {
+		case codes.Canceled:
+			target = context.Canceled
+		case codes.DeadlineExceeded:
+			target = context.DeadlineExceeded
+		default:
+			if target = handler.ErrorFromStatus(httpStatusFromCode(st.Code())); target == nil {
+				return err
+			}
+		}
+		return fmt.Errorf("%w: %s", target, st.Message())
+	}
+}
+
+// httpStatusFromCode 返回 gRPC 状态码对应的 HTTP 状态码，以便与其他传输共用 handler.ErrorFromStatus 的映射
+// 没有对应关系时返回 0
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	default:
+		return 0
+	}
+}
+
+func mergeOptions[I, O any](
+	options ...ClientOptionFunc,
+) *ClientOptions {
+	opts := ClientOptions{
+		encoder:      DefaultRequestEncoder[I](),
+		decoder:      DefaultResponseDecoder[O](),
+		errorHandler: DefaultErrorHandler(),
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return &opts
+}
+
+// NewClient 创建一个通用的 gRPC 客户端
+// 通过 conn.Invoke 调用 method（如 "/user.UserService/CreateUser"），请求和响应消息使用 JSON 编码，
+// content-subtype 为 "json"，服务端需注册同名的 JSON 编解码器
+func NewClient[I, O any](
+	conn grpc.ClientConnInterface,
+	method string,
+	options ...ClientOptionFunc,
+) handler.HandlerFunc[I, O] {
+	opts := mergeOptions[I, O](options...)
+	callOptions := append([]grpc.CallOption{grpc.ForceCodec(rawCodec{})}, opts.callOptions...)
+
+	return func(ctx context.Context, input I) (O, error) {
+		var zero O
+
+		// 编码请求
+		req, err := opts.encoder(input)
+		if err != nil {
+			return zero, err
+		}
+
+		// 发送请求
+		var reply []byte
+		err = conn.Invoke(ctx, method, req, &reply, callOptions...)
+
+		// 错误处理
+		if err := opts.errorHandler(err); err != nil {
+			return zero, err
+		}
+
+		// 解码响应
+		resultAny, err := opts.decoder(reply)
+		if err != nil {
+			return zero, err
+		}
+
+		// 类型断言
+		result, ok := resultAny.(O)
+		if !ok {
+			return zero, ErrDecoderReturnedWrongType
+		}
+
+		return result, nil
+	}
+}
+
+// NewAction 创建无输入输出的客户端处理器
+// 适用场景：触发任务、执行操作等不需要请求参数和响应数据的场景
+func NewAction(
+	conn grpc.ClientConnInterface,
+	method string,
+	options ...ClientOptionFunc,
+) handler.ActionHandlerFunc {
+	handler := NewClient[struct{}, struct{}](conn, method, options...)
+	return func(ctx context.Context) error {
+		_, err := handler(ctx, struct{}{})
+		return err
+	}
+}
+
+// NewGetter 创建只有输出的客户端处理器
+// 适用场景：获取数据、健康检查等不需要请求参数的查询场景
+func NewGetter[O any](
+	conn grpc.ClientConnInterface,
+	method string,
+	options ...ClientOptionFunc,
+) handler.GetterHandlerFunc[O] {
+	handler := NewClient[struct{}, O](conn, method, options...)
+	return func(ctx context.Context) (O, error) {
+		return handler(ctx, struct{}{})
+	}
+}
+
+// NewConsumer 创建只有输入的客户端处理器
+// 适用场景：删除操作、更新操作等不需要返回数据的场景
+func NewConsumer[I any](
+	conn grpc.ClientConnInterface,
+	method string,
+	options ...ClientOptionFunc,
+) handler.ConsumerHandlerFunc[I] {
+	handler := NewClient[I, struct{}](conn, method, options...)
+	return func(ctx context.Context, args I) error {
+		_, err := handler(ctx, args)
+		return err
+	}
+}
@@ -0,0 +1,215 @@
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Test types
+type TestRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type TestResponse struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// newTestConn 启动一个按方法名分发 JSON 消息的 gRPC 服务，返回连接到它的客户端
+func newTestConn(t *testing.T, methods map[string]func(ctx context.Context, req []byte) ([]byte, error)) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(srv any, stream grpc.ServerStream) error {
+			method, _ := grpc.MethodFromServerStream(stream)
+			h, ok := methods[method]
+			if !ok {
+				return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+			}
+			var req []byte
+			if err := stream.RecvMsg(&req); err != nil {
+				return err
+			}
+			resp, err := h(stream.Context(), req)
+			if err != nil {
+				return err
+			}
+			return stream.SendMsg(resp)
+		}),
+	)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// TestNewClient tests the NewClient functionality
+func TestNewClient(t *testing.T) {
+	conn := newTestConn(t, map[string]func(ctx context.Context, req []byte) ([]byte, error){
+		"/user.UserService/CreateUser": func(ctx context.Context, data []byte) ([]byte, error) {
+			var req TestRequest
+			if err := json.Unmarshal(data, &req); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			if req.Email == "" {
+				return nil, status.Error(codes.InvalidArgument, "email is required")
+			}
+			return json.Marshal(TestResponse{ID: 1, Name: req.Name, Email: req.Email})
+		},
+	})
+
+	client := NewClient[TestRequest, TestResponse](conn, "/user.UserService/CreateUser")
+
+	t.Run("success", func(t *testing.T) {
+		result, err := client(context.Background(), TestRequest{Name: "Alice", Email: "alice@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, TestResponse{ID: 1, Name: "Alice", Email: "alice@example.com"}, result)
+	})
+
+	t.Run("status_error", func(t *testing.T) {
+		_, err := client(context.Background(), TestRequest{Name: "Alice"})
+
+		assert.ErrorIs(t, err, handler.ErrBadRequest)
+		assert.Contains(t, err.Error(), "email is required")
+	})
+}
+
+// TestHandlerVariants tests the NewGetter, NewConsumer and NewAction functionality
+func TestHandlerVariants(t *testing.T) {
+	var consumed TestRequest
+	triggered := false
+	conn := newTestConn(t, map[string]func(ctx context.Context, req []byte) ([]byte, error){
+		"/user.UserService/Health": func(ctx context.Context, data []byte) ([]byte, error) {
+			return []byte(`{"name":"ok"}`), nil
+		},
+		"/user.UserService/DeleteUser": func(ctx context.Context, data []byte) ([]byte, error) {
+			return nil, json.Unmarshal(data, &consumed)
+		},
+		"/user.UserService/Trigger": func(ctx context.Context, data []byte) ([]byte, error) {
+			triggered = true
+			return nil, nil
+		},
+		"/user.UserService/Missing": func(ctx context.Context, data []byte) ([]byte, error) {
+			return nil, status.Error(codes.NotFound, "user 7")
+		},
+		"/user.UserService/Throttled": func(ctx context.Context, data []byte) ([]byte, error) {
+			return nil, status.Error(codes.ResourceExhausted, "quota exceeded")
+		},
+	})
+
+	t.Run("getter", func(t *testing.T) {
+		result, err := NewGetter[TestResponse](conn, "/user.UserService/Health")(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", result.Name)
+	})
+
+	t.Run("consumer", func(t *testing.T) {
+		err := NewConsumer[TestRequest](conn, "/user.UserService/DeleteUser")(context.Background(), TestRequest{Name: "Bob"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bob", consumed.Name)
+	})
+
+	t.Run("action", func(t *testing.T) {
+		err := NewAction(conn, "/user.UserService/Trigger")(context.Background())
+
+		assert.NoError(t, err)
+		assert.True(t, triggered)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		err := NewAction(conn, "/user.UserService/Missing")(context.Background())
+
+		assert.ErrorIs(t, err, handler.ErrNotFound)
+	})
+
+	t.Run("resource_exhausted", func(t *testing.T) {
+		err := NewAction(conn, "/user.UserService/Throttled")(context.Background())
+
+		assert.ErrorIs(t, err, handler.ErrTooManyRequests)
+	})
+}
+
+// TestContextCancellation tests that context deadlines abort pending calls
+func TestContextCancellation(t *testing.T) {
+	conn := newTestConn(t, map[string]func(ctx context.Context, req []byte) ([]byte, error){
+		"/user.UserService/Slow": func(ctx context.Context, data []byte) ([]byte, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := NewAction(conn, "/user.UserService/Slow")(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// TestCustomOptions tests the custom encoder, decoder and error handler options
+func TestCustomOptions(t *testing.T) {
+	conn := newTestConn(t, map[string]func(ctx context.Context, req []byte) ([]byte, error){
+		"/echo.Echo/Echo": func(ctx context.Context, data []byte) ([]byte, error) {
+			return data, nil
+		},
+		"/echo.Echo/Fail": func(ctx context.Context, data []byte) ([]byte, error) {
+			return nil, status.Error(codes.Internal, "boom")
+		},
+	})
+
+	t.Run("encoder_decoder", func(t *testing.T) {
+		client := NewClient[string, string](
+			conn,
+			"/echo.Echo/Echo",
+			WithEncoder(func(input any) ([]byte, error) {
+				return []byte(input.(string)), nil
+			}),
+			WithDecoder(func(data []byte) (any, error) {
+				return "echo: " + string(data), nil
+			}),
+		)
+
+		result, err := client(context.Background(), "hello")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: hello", result)
+	})
+
+	t.Run("error_handler", func(t *testing.T) {
+		customErr := errors.New("custom")
+		err := NewAction(conn, "/echo.Echo/Fail", WithErrorHandler(func(err error) error {
+			if err != nil {
+				return customErr
+			}
+			return nil
+		}))(context.Background())
+
+		assert.ErrorIs(t, err, customErr)
+	})
+}
@@ -0,0 +1,33 @@
+package grpcclient
+
+import "fmt"
+
+// CodecName JSON 编解码器的名称，即 gRPC 的 content-subtype
+const CodecName = "json"
+
+// rawCodec 原样传输已编码的消息字节，实际的 JSON 编解码由编码器和解码器完成
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	switch msg := v.(type) {
+	case []byte:
+		return msg, nil
+	case *[]byte:
+		return *msg, nil
+	default:
+		return nil, fmt.Errorf("grpcclient: unexpected message type %T", v)
+	}
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("grpcclient: unexpected message type %T", v)
+	}
+	*msg = append((*msg)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return CodecName
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+)
+
+// 标准错误定义
+// 业务层可通过 fmt.Errorf("...: %w", ErrNotFound) 包装领域错误，
+// 传输层（如 gin-server）通过 errors.Is 将其映射为对应的状态码
+var (
+	ErrBadRequest      = errors.New("bad request")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrNotFound        = errors.New("not found")
+	ErrConflict        = errors.New("conflict")
+	ErrGone            = errors.New("gone") // 资源已被永久移除，与 ErrNotFound 不同，客户端不应再重试
+	ErrUnprocessable   = errors.New("unprocessable entity")
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// statusErrors 标准错误与 HTTP 状态码的对应关系，各传输层共用同一份映射
+var statusErrors = []struct {
+	status int
+	err    error
+}{
+	{http.StatusBadRequest, ErrBadRequest},
+	{http.StatusUnauthorized, ErrUnauthorized},
+	{http.StatusForbidden, ErrForbidden},
+	{http.StatusNotFound, ErrNotFound},
+	{http.StatusConflict, ErrConflict},
+	{http.StatusGone, ErrGone},
+	{http.StatusUnprocessableEntity, ErrUnprocessable},
+	{http.StatusTooManyRequests, ErrTooManyRequests},
+}
+
+// StatusFromError 根据标准错误返回对应的 HTTP 状态码，无法识别的错误返回 500
+// 非 HTTP 传输（如 NATS）的错误码取值与 HTTP 状态码一致，同样使用该函数
+func StatusFromError(err error) int {
+	for _, se := range statusErrors {
+		if errors.Is(err, se.err) {
+			return se.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorFromStatus 返回 HTTP 状态码对应的标准错误，是 StatusFromError 的逆映射，没有对应的标准错误时返回 nil
+func ErrorFromStatus(status int) error {
+	for _, se := range statusErrors {
+		if se.status == status {
+			return se.err
+		}
+	}
+	return nil
+}
+
+// ErrorDetail 错误的单条详细信息，如某个字段校验失败的原因
+type ErrorDetail struct {
+	Field string `json:"field"` // 出错的字段或位置
+	Issue string `json:"issue"` // 具体问题
+}
+
+// Detailer 可提供详细信息的错误
+// 传输层（如 gin-server）的默认错误处理器会将 Details 一并返回给客户端
+type Detailer interface {
+	Details() []ErrorDetail
+}
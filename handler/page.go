@@ -0,0 +1,69 @@
+package handler
+
+import "encoding/json"
+
+// CursorPage 基于游标的分页结果
+// 序列化为 {"items":[...],"next_cursor":"...","has_more":true}，没有条目时输出空数组而非 null
+// HasMore 为 true 时，客户端使用 NextCursor 请求下一页
+type CursorPage[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
+func (p CursorPage[T]) MarshalJSON() ([]byte, error) {
+	type cursorPage CursorPage[T]
+	out := cursorPage(p)
+	if out.Items == nil {
+		out.Items = []T{}
+	}
+	return json.Marshal(out)
+}
+
+// Page 基于页码的分页参数，Number 从 1 开始
+type Page struct {
+	Number int // 页码
+	Size   int // 每页条数
+}
+
+// Offset 返回当前页第一条记录的偏移量，可直接用于 SQL 的 OFFSET
+func (p Page) Offset() int {
+	if p.Number < 1 {
+		return 0
+	}
+	return (p.Number - 1) * p.Size
+}
+
+// PageResponse 基于页码的分页结果
+// 序列化为 {"items":[...],"page":1,"page_size":10,"total":42,"total_pages":5}，没有条目时输出空数组而非 null
+type PageResponse[T any] struct {
+	Items      []T   `json:"items"`
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int64 `json:"total_pages"`
+}
+
+// NewPageResponse 根据分页参数和总数组装分页结果，并计算总页数
+func NewPageResponse[T any](items []T, page Page, total int64) PageResponse[T] {
+	var totalPages int64
+	if page.Size > 0 {
+		totalPages = (total + int64(page.Size) - 1) / int64(page.Size)
+	}
+	return PageResponse[T]{
+		Items:      items,
+		Page:       page.Number,
+		PageSize:   page.Size,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
+func (p PageResponse[T]) MarshalJSON() ([]byte, error) {
+	type pageResponse PageResponse[T]
+	out := pageResponse(p)
+	if out.Items == nil {
+		out.Items = []T{}
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import "encoding/json"
+
+// ItemError 批量操作中单个条目的错误
+type ItemError struct {
+	Index int    `json:"index"` // 条目在请求中的下标
+	Error string `json:"error"` // 错误信息
+}
+
+// PartialSuccess 批量操作的部分成功结果
+// 整体请求成功（200），成功条目的结果写入 Results，失败条目的错误写入 Errors
+// 序列化为 {"results":[...],"errors":[...]}，没有条目时输出空数组而非 null
+type PartialSuccess[O any] struct {
+	Results []O         `json:"results"`
+	Errors  []ItemError `json:"errors"`
+}
+
+// Succeed 记录一个成功条目的结果
+func (p *PartialSuccess[O]) Succeed(result O) {
+	p.Results = append(p.Results, result)
+}
+
+// Fail 记录下标为 index 的条目的错误
+func (p *PartialSuccess[O]) Fail(index int, err error) {
+	p.Errors = append(p.Errors, ItemError{Index: index, Error: err.Error()})
+}
+
+// HasErrors 是否存在失败的条目
+func (p PartialSuccess[O]) HasErrors() bool {
+	return len(p.Errors) > 0
+}
+
+func (p PartialSuccess[O]) MarshalJSON() ([]byte, error) {
+	type partialSuccess PartialSuccess[O]
+	out := partialSuccess(p)
+	if out.Results == nil {
+		out.Results = []O{}
+	}
+	if out.Errors == nil {
+		out.Errors = []ItemError{}
+	}
+	return json.Marshal(out)
+}
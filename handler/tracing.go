@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// DefaultRequestIDHeader 默认传递请求 ID 的请求头/响应头
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// DefaultRequestIDLogKey 默认日志记录中请求 ID 的属性名
+const DefaultRequestIDLogKey = "request_id"
+
+// TracingConfig 请求 ID 关联配置
+// 服务端（ginserver.WithTracing）和客户端（restyclient.WithTracing）使用同一份配置，
+// 请求 ID 通过同一个请求头在服务间传递，并以同一个属性名写入两端的日志
+type TracingConfig struct {
+	Header string       // 传递请求 ID 的请求头，为空时使用 DefaultRequestIDHeader
+	LogKey string       // 日志记录中请求 ID 的属性名，为空时使用 DefaultRequestIDLogKey
+	Logger *slog.Logger // 输出请求日志的 logger，为 nil 时不输出日志
+}
+
+// HeaderName 返回传递请求 ID 的请求头
+func (cfg TracingConfig) HeaderName() string {
+	if cfg.Header == "" {
+		return DefaultRequestIDHeader
+	}
+	return cfg.Header
+}
+
+// LogKeyName 返回日志记录中请求 ID 的属性名
+func (cfg TracingConfig) LogKeyName() string {
+	if cfg.LogKey == "" {
+		return DefaultRequestIDLogKey
+	}
+	return cfg.LogKey
+}
+
+// requestIDCtxKey 请求 ID 在 context 中的 key
+type requestIDCtxKey struct{}
+
+// ContextWithRequestID 返回携带请求 ID 的 ctx，客户端发出的请求会携带该请求 ID
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+// RequestIDFromContext 返回 ctx 中的请求 ID，没有时返回空字符串
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return id
+}
+
+// NewRequestID 生成 16 字节的随机十六进制请求 ID
+func NewRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
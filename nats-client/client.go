@@ -0,0 +1,228 @@
+package natsclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+type RequestEncoderFunc func(msg *nats.Msg, input any) error
+
+type ResponseDecoderFunc func(msg *nats.Msg) (any, error)
+
+// ErrorHandlerFunc 错误处理器，请求失败时 msg 为 nil
+type ErrorHandlerFunc func(msg *nats.Msg, err error) error
+
+// 服务端通过回复消息的请求头返回错误，与 NATS micro 框架的约定一致
+const (
+	ErrorHeader     = "Nats-Service-Error"
+	ErrorCodeHeader = "Nats-Service-Error-Code"
+)
+
+// DefaultTimeout 默认的请求超时时间
+const DefaultTimeout = 5 * time.Second
+
+// 错误定义
+var ErrDecoderReturnedWrongType = errors.New("decoder returned wrong type")
+
+type ClientOptions struct {
+	encoder      RequestEncoderFunc
+	decoder      ResponseDecoderFunc
+	errorHandler ErrorHandlerFunc
+	timeout      time.Duration
+}
+
+type ClientOptionFunc func(*ClientOptions)
+
+func WithEncoder(encoder RequestEncoderFunc) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.encoder = encoder
+	}
+}
+
+func WithDecoder(decoder ResponseDecoderFunc) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.decoder = decoder
+	}
+}
+
+func WithErrorHandler(errHandler ErrorHandlerFunc) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.errorHandler = errHandler
+	}
+}
+
+// WithTimeout 设置等待回复的超时时间，ctx 的截止时间更早时以 ctx 为准
+// 小于等于 0 表示只使用 ctx 控制超时
+func WithTimeout(timeout time.Duration) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.timeout = timeout
+	}
+}
+
+// DefaultRequestEncoder 默认请求编码器
+// 将输入序列化为 JSON 作为请求消息
+func DefaultRequestEncoder[I any]() RequestEncoderFunc {
+	return func(msg *nats.Msg, input any) error {
+		data, err := json.Marshal(input)
+		if err != nil {
+			return err
+		}
+		msg.Data = data
+		return nil
+	}
+}
+
+// DefaultResponseDecoder 默认响应解码器
+// 将回复消息反序列化为目标类型，空消息返回零值
+func DefaultResponseDecoder[O any]() ResponseDecoderFunc {
+	return func(msg *nats.Msg) (any, error) {
+		var result O
+		if len(msg.Data) == 0 {
+			return result, nil
+		}
+		if err := json.Unmarshal(msg.Data, &result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+}
+
+// DefaultErrorHandler 默认错误处理器
+// 请求超时映射为 context.DeadlineExceeded；回复消息携带错误请求头时，将错误码按 HTTP 状态码映射为 handler 包中的标准错误
+// 业务层可继续使用 errors.Is 判断错误类型，与 HTTP 传输保持一致
+func DefaultErrorHandler() ErrorHandlerFunc {
+	return func(msg *nats.Msg, err error) error {
+		if err != nil {
+			if errors.Is(err, nats.ErrTimeout) {
+				return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+			}
+			return err
+		}
+		if msg.Header == nil {
+			return nil
+		}
+		code := msg.Header.Get(ErrorCodeHeader)
+		desc := msg.Header.Get(ErrorHeader)
+		if code == "" && desc == "" {
+			return nil
+		}
+
+		status, _ := strconv.Atoi(code)
+		target := handler.ErrorFromStatus(status)
+		if target == nil {
+			return fmt.Errorf("service error %s: %s", code, desc)
+		}
+		return fmt.Errorf("%w: %s", target, desc)
+	}
+}
+
+func mergeOptions[I, O any](
+	options ...ClientOptionFunc,
+) *ClientOptions {
+	opts := ClientOptions{
+		encoder:      DefaultRequestEncoder[I](),
+		decoder:      DefaultResponseDecoder[O](),
+		errorHandler: DefaultErrorHandler(),
+		timeout:      DefaultTimeout,
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return &opts
+}
+
+// NewClient 创建一个通用的 NATS request/reply 客户端
+// 将输入编码后发布到 subject 并等待回复，回复消息解码为 O；ctx 取消时放弃等待中的请求
+func NewClient[I, O any](
+	nc *nats.Conn,
+	subject string,
+	options ...ClientOptionFunc,
+) handler.HandlerFunc[I, O] {
+	opts := mergeOptions[I, O](options...)
+
+	return func(ctx context.Context, input I) (O, error) {
+		var zero O
+
+		// 编码请求
+		msg := nats.NewMsg(subject)
+		if err := opts.encoder(msg, input); err != nil {
+			return zero, err
+		}
+
+		if opts.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+			defer cancel()
+		}
+
+		// 发送请求
+		reply, err := nc.RequestMsgWithContext(ctx, msg)
+
+		// 错误处理
+		if err := opts.errorHandler(reply, err); err != nil {
+			return zero, err
+		}
+
+		// 解码响应
+		resultAny, err := opts.decoder(reply)
+		if err != nil {
+			return zero, err
+		}
+
+		// 类型断言
+		result, ok := resultAny.(O)
+		if !ok {
+			return zero, ErrDecoderReturnedWrongType
+		}
+
+		return result, nil
+	}
+}
+
+// NewAction 创建无输入输出的客户端处理器
+// 适用场景：触发任务、执行操作等不需要请求参数和响应数据的场景
+func NewAction(
+	nc *nats.Conn,
+	subject string,
+	options ...ClientOptionFunc,
+) handler.ActionHandlerFunc {
+	handler := NewClient[struct{}, struct{}](nc, subject, options...)
+	return func(ctx context.Context) error {
+		_, err := handler(ctx, struct{}{})
+		return err
+	}
+}
+
+// NewGetter 创建只有输出的客户端处理器
+// 适用场景：获取数据、健康检查等不需要请求参数的查询场景
+func NewGetter[O any](
+	nc *nats.Conn,
+	subject string,
+	options ...ClientOptionFunc,
+) handler.GetterHandlerFunc[O] {
+	handler := NewClient[struct{}, O](nc, subject, options...)
+	return func(ctx context.Context) (O, error) {
+		return handler(ctx, struct{}{})
+	}
+}
+
+// NewConsumer 创建只有输入的客户端处理器
+// 适用场景：删除操作、更新操作等不需要返回数据的场景
+func NewConsumer[I any](
+	nc *nats.Conn,
+	subject string,
+	options ...ClientOptionFunc,
+) handler.ConsumerHandlerFunc[I] {
+	handler := NewClient[I, struct{}](nc, subject, options...)
+	return func(ctx context.Context, args I) error {
+		_, err := handler(ctx, args)
+		return err
+	}
+}
@@ -0,0 +1,226 @@
+package natsclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// Test types
+type TestRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type TestResponse struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// newTestConn 启动一个内嵌的 NATS 服务，返回连接到它的客户端
+func newTestConn(t *testing.T) *nats.Conn {
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	assert.NoError(t, err)
+	go s.Start()
+	t.Cleanup(s.Shutdown)
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	nc, err := nats.Connect(s.ClientURL())
+	assert.NoError(t, err)
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+// respondError 以 NATS micro 的约定回复错误
+func respondError(msg *nats.Msg, code, desc string) {
+	reply := nats.NewMsg(msg.Reply)
+	reply.Header.Set(ErrorCodeHeader, code)
+	reply.Header.Set(ErrorHeader, desc)
+	msg.RespondMsg(reply)
+}
+
+// TestNewClient tests the NewClient functionality
+func TestNewClient(t *testing.T) {
+	nc := newTestConn(t)
+	_, err := nc.Subscribe("user.create", func(msg *nats.Msg) {
+		var req TestRequest
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			respondError(msg, "400", err.Error())
+			return
+		}
+		if req.Email == "" {
+			respondError(msg, "400", "email is required")
+			return
+		}
+		data, _ := json.Marshal(TestResponse{ID: 1, Name: req.Name, Email: req.Email})
+		msg.Respond(data)
+	})
+	assert.NoError(t, err)
+
+	client := NewClient[TestRequest, TestResponse](nc, "user.create")
+
+	t.Run("success", func(t *testing.T) {
+		result, err := client(context.Background(), TestRequest{Name: "Alice", Email: "alice@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, TestResponse{ID: 1, Name: "Alice", Email: "alice@example.com"}, result)
+	})
+
+	t.Run("service_error", func(t *testing.T) {
+		_, err := client(context.Background(), TestRequest{Name: "Alice"})
+
+		assert.ErrorIs(t, err, handler.ErrBadRequest)
+		assert.Contains(t, err.Error(), "email is required")
+	})
+
+	t.Run("no_responders", func(t *testing.T) {
+		_, err := NewClient[TestRequest, TestResponse](nc, "user.missing")(context.Background(), TestRequest{})
+
+		assert.ErrorIs(t, err, nats.ErrNoResponders)
+	})
+}
+
+// TestHandlerVariants tests the NewGetter, NewConsumer and NewAction functionality
+func TestHandlerVariants(t *testing.T) {
+	nc := newTestConn(t)
+	consumed := make(chan TestRequest, 1)
+	triggered := make(chan struct{}, 1)
+	nc.Subscribe("user.health", func(msg *nats.Msg) {
+		msg.Respond([]byte(`{"name":"ok"}`))
+	})
+	nc.Subscribe("user.delete", func(msg *nats.Msg) {
+		var req TestRequest
+		json.Unmarshal(msg.Data, &req)
+		consumed <- req
+		msg.Respond(nil)
+	})
+	nc.Subscribe("task.trigger", func(msg *nats.Msg) {
+		triggered <- struct{}{}
+		msg.Respond(nil)
+	})
+	nc.Subscribe("user.get", func(msg *nats.Msg) {
+		respondError(msg, "404", "user 7")
+	})
+	nc.Subscribe("user.throttled", func(msg *nats.Msg) {
+		respondError(msg, "429", "slow down")
+	})
+
+	t.Run("getter", func(t *testing.T) {
+		result, err := NewGetter[TestResponse](nc, "user.health")(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", result.Name)
+	})
+
+	t.Run("consumer", func(t *testing.T) {
+		err := NewConsumer[TestRequest](nc, "user.delete")(context.Background(), TestRequest{Name: "Bob"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bob", (<-consumed).Name)
+	})
+
+	t.Run("action", func(t *testing.T) {
+		err := NewAction(nc, "task.trigger")(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, triggered, 1)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		err := NewAction(nc, "user.get")(context.Background())
+
+		assert.ErrorIs(t, err, handler.ErrNotFound)
+	})
+
+	t.Run("too_many_requests", func(t *testing.T) {
+		err := NewAction(nc, "user.throttled")(context.Background())
+
+		assert.ErrorIs(t, err, handler.ErrTooManyRequests)
+	})
+}
+
+// TestTimeoutAndCancellation tests that timeouts and context cancellation abort pending requests
+func TestTimeoutAndCancellation(t *testing.T) {
+	nc := newTestConn(t)
+	// 订阅但从不回复
+	nc.Subscribe("task.slow", func(msg *nats.Msg) {})
+
+	t.Run("timeout", func(t *testing.T) {
+		err := NewAction(nc, "task.slow", WithTimeout(20*time.Millisecond))(context.Background())
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		start := time.Now()
+		err := NewAction(nc, "task.slow")(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), DefaultTimeout)
+	})
+}
+
+// TestCustomOptions tests the custom encoder, decoder and error handler options
+func TestCustomOptions(t *testing.T) {
+	nc := newTestConn(t)
+	nc.Subscribe("echo", func(msg *nats.Msg) {
+		reply := nats.NewMsg(msg.Reply)
+		reply.Data = msg.Data
+		reply.Header.Set("X-Trace", msg.Header.Get("X-Trace"))
+		msg.RespondMsg(reply)
+	})
+	nc.Subscribe("fail", func(msg *nats.Msg) {
+		respondError(msg, "500", "boom")
+	})
+
+	t.Run("encoder_decoder", func(t *testing.T) {
+		client := NewClient[string, string](
+			nc,
+			"echo",
+			WithEncoder(func(msg *nats.Msg, input any) error {
+				msg.Data = []byte(input.(string))
+				msg.Header.Set("X-Trace", "abc")
+				return nil
+			}),
+			WithDecoder(func(msg *nats.Msg) (any, error) {
+				return msg.Header.Get("X-Trace") + ": " + string(msg.Data), nil
+			}),
+		)
+
+		result, err := client(context.Background(), "hello")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abc: hello", result)
+	})
+
+	t.Run("default_service_error", func(t *testing.T) {
+		err := NewAction(nc, "fail")(context.Background())
+
+		assert.EqualError(t, err, "service error 500: boom")
+	})
+
+	t.Run("error_handler", func(t *testing.T) {
+		customErr := errors.New("custom")
+		err := NewAction(nc, "fail", WithErrorHandler(func(msg *nats.Msg, err error) error {
+			if err == nil && msg.Header.Get(ErrorCodeHeader) != "" {
+				return customErr
+			}
+			return err
+		}))(context.Background())
+
+		assert.ErrorIs(t, err, customErr)
+	})
+}
@@ -0,0 +1,238 @@
+package natsserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+type DecoderFunc func(msg *nats.Msg) (any, error)
+
+type EncoderFunc func(reply *nats.Msg, output any) error
+
+type ErrorHandlerFunc func(reply *nats.Msg, err error)
+
+// 通过回复消息的请求头返回错误，与 NATS micro 框架及 natsclient 的约定一致
+const (
+	ErrorHeader     = "Nats-Service-Error"
+	ErrorCodeHeader = "Nats-Service-Error-Code"
+)
+
+// 错误定义
+var ErrDecoderReturnedWrongType = errors.New("decoder returned wrong type")
+var ErrHandlerPanicked = errors.New("handler panicked")
+
+type SubscribeOptions struct {
+	decoder      DecoderFunc
+	encoder      EncoderFunc
+	errorHandler ErrorHandlerFunc
+	queue        string
+}
+
+type SubscribeOptionFunc func(*SubscribeOptions)
+
+func WithDecoder(decoder DecoderFunc) SubscribeOptionFunc {
+	return func(opts *SubscribeOptions) {
+		opts.decoder = decoder
+	}
+}
+
+func WithEncoder(encoder EncoderFunc) SubscribeOptionFunc {
+	return func(opts *SubscribeOptions) {
+		opts.encoder = encoder
+	}
+}
+
+func WithErrorHandler(errHandler ErrorHandlerFunc) SubscribeOptionFunc {
+	return func(opts *SubscribeOptions) {
+		opts.errorHandler = errHandler
+	}
+}
+
+// WithQueueGroup 以队列订阅的方式订阅，同一队列组内的订阅者分摊请求
+func WithQueueGroup(queue string) SubscribeOptionFunc {
+	return func(opts *SubscribeOptions) {
+		opts.queue = queue
+	}
+}
+
+// DefaultDecoder 默认解码器
+// 将请求消息反序列化为目标类型，空消息解码为零值；解码失败时返回包装了 handler.ErrBadRequest 的错误
+func DefaultDecoder[I any]() DecoderFunc {
+	return func(msg *nats.Msg) (any, error) {
+		var args I
+		if len(msg.Data) == 0 {
+			return args, nil
+		}
+		if err := json.Unmarshal(msg.Data, &args); err != nil {
+			return nil, fmt.Errorf("%w: %w", handler.ErrBadRequest, err)
+		}
+		return args, nil
+	}
+}
+
+// DefaultEncoder 默认编码器
+// 将输出序列化为 JSON 作为回复消息
+func DefaultEncoder[O any]() EncoderFunc {
+	return func(reply *nats.Msg, output any) error {
+		data, err := json.Marshal(output)
+		if err != nil {
+			return err
+		}
+		reply.Data = data
+		return nil
+	}
+}
+
+// DefaultErrorHandler 默认错误处理器
+// 在回复消息的请求头中写入错误码（与 HTTP 状态码一致）和错误描述，消息体为 {"error": ...} 形式的标准错误信封
+// 错误实现了 handler.Detailer 时，error 字段包含 message 和 details
+func DefaultErrorHandler() ErrorHandlerFunc {
+	return func(reply *nats.Msg, err error) {
+		reply.Header.Set(ErrorCodeHeader, strconv.Itoa(CodeFromError(err)))
+		reply.Header.Set(ErrorHeader, err.Error())
+		reply.Data, _ = json.Marshal(map[string]any{"error": errorPayload(err)})
+	}
+}
+
+// errorPayload 生成错误信封中 error 字段的内容，没有详细信息时保持字符串形式
+func errorPayload(err error) any {
+	var detailer handler.Detailer
+	if errors.As(err, &detailer) {
+		if details := detailer.Details(); len(details) > 0 {
+			return map[string]any{"message": err.Error(), "details": details}
+		}
+	}
+	return err.Error()
+}
+
+// CodeFromError 根据 handler 包中的标准错误返回对应的错误码，取值与 HTTP 状态码一致，映射与 handler.StatusFromError 相同
+// 无法识别的错误返回 500
+func CodeFromError(err error) int {
+	return handler.StatusFromError(err)
+}
+
+func mergeOptions[I, O any](
+	options ...SubscribeOptionFunc,
+) *SubscribeOptions {
+	opts := SubscribeOptions{
+		decoder:      DefaultDecoder[I](),
+		encoder:      DefaultEncoder[O](),
+		errorHandler: DefaultErrorHandler(),
+	}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	return &opts
+}
+
+// SubscribeHandler 订阅 subject，将请求消息解码为 I 交给处理器，并把编码后的 O 发布到回复主题
+// 处理过程中的错误交给错误处理器写入回复消息；没有回复主题的消息只执行处理器，不发送回复
+// 解码器、处理器或编码器 panic 时不会导致进程退出，以包装了 ErrHandlerPanicked 的错误（错误码 500）交给错误处理器
+// 返回的订阅可用于退订，处理器收到的 ctx 在消息处理结束时取消
+func SubscribeHandler[I, O any](
+	nc *nats.Conn,
+	subject string,
+	h handler.HandlerFunc[I, O],
+	options ...SubscribeOptionFunc,
+) (*nats.Subscription, error) {
+	opts := mergeOptions[I, O](options...)
+
+	cb := func(msg *nats.Msg) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		reply := nats.NewMsg(msg.Reply)
+		err := func() (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					// 丢弃 panic 前可能已部分写入的回复内容
+					reply = nats.NewMsg(msg.Reply)
+					err = fmt.Errorf("%w: %v", ErrHandlerPanicked, r)
+				}
+			}()
+			return handleMsg(ctx, msg, reply, h, opts)
+		}()
+		if err != nil {
+			opts.errorHandler(reply, err)
+		}
+		if msg.Reply == "" {
+			return
+		}
+		msg.RespondMsg(reply)
+	}
+
+	if opts.queue != "" {
+		return nc.QueueSubscribe(subject, opts.queue, cb)
+	}
+	return nc.Subscribe(subject, cb)
+}
+
+// handleMsg 解码请求、执行处理器并将输出编码到回复消息
+func handleMsg[I, O any](
+	ctx context.Context,
+	msg, reply *nats.Msg,
+	h handler.HandlerFunc[I, O],
+	opts *SubscribeOptions,
+) error {
+	argAny, err := opts.decoder(msg)
+	if err != nil {
+		return err
+	}
+
+	// 类型断言
+	args, ok := argAny.(I)
+	if !ok {
+		return ErrDecoderReturnedWrongType
+	}
+
+	output, err := h(ctx, args)
+	if err != nil {
+		return err
+	}
+	return opts.encoder(reply, output)
+}
+
+// SubscribeAction 订阅无输入输出的处理器
+// 适用场景：触发任务、执行操作等不需要请求参数和响应数据的场景
+func SubscribeAction(
+	nc *nats.Conn,
+	subject string,
+	h handler.ActionHandlerFunc,
+	options ...SubscribeOptionFunc,
+) (*nats.Subscription, error) {
+	return SubscribeHandler(nc, subject, func(ctx context.Context, _ struct{}) (struct{}, error) {
+		return struct{}{}, h(ctx)
+	}, options...)
+}
+
+// SubscribeGetter 订阅只有输出的处理器
+// 适用场景：获取数据、健康检查等不需要请求参数的查询场景
+func SubscribeGetter[O any](
+	nc *nats.Conn,
+	subject string,
+	h handler.GetterHandlerFunc[O],
+	options ...SubscribeOptionFunc,
+) (*nats.Subscription, error) {
+	return SubscribeHandler(nc, subject, func(ctx context.Context, _ struct{}) (O, error) {
+		return h(ctx)
+	}, options...)
+}
+
+// SubscribeConsumer 订阅只有输入的处理器
+// 适用场景：删除操作、更新操作等不需要返回数据的场景
+func SubscribeConsumer[I any](
+	nc *nats.Conn,
+	subject string,
+	h handler.ConsumerHandlerFunc[I],
+	options ...SubscribeOptionFunc,
+) (*nats.Subscription, error) {
+	return SubscribeHandler(nc, subject, func(ctx context.Context, args I) (struct{}, error) {
+		return struct{}{}, h(ctx, args)
+	}, options...)
+}
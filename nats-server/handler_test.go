@@ -0,0 +1,232 @@
+package natsserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+	natsclient "github.com/zhangzqs/go-typed-rpc/nats-client"
+)
+
+// Test types
+type TestRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type TestResponse struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// detailedError 携带详细信息的测试错误
+type detailedError struct{}
+
+func (detailedError) Error() string { return "validation failed" }
+
+func (detailedError) Unwrap() error { return handler.ErrBadRequest }
+
+func (detailedError) Details() []handler.ErrorDetail {
+	return []handler.ErrorDetail{{Field: "email", Issue: "required"}}
+}
+
+// newTestConn 启动一个内嵌的 NATS 服务，返回连接到它的客户端
+func newTestConn(t *testing.T) *nats.Conn {
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	assert.NoError(t, err)
+	go s.Start()
+	t.Cleanup(s.Shutdown)
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	nc, err := nats.Connect(s.ClientURL())
+	assert.NoError(t, err)
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+// TestSubscribeHandler tests serving a typed handler over NATS request/reply
+func TestSubscribeHandler(t *testing.T) {
+	nc := newTestConn(t)
+	sub, err := SubscribeHandler(nc, "user.create", func(ctx context.Context, req TestRequest) (TestResponse, error) {
+		switch req.Name {
+		case "exists":
+			return TestResponse{}, fmt.Errorf("user %s: %w", req.Name, handler.ErrConflict)
+		case "invalid":
+			return TestResponse{}, detailedError{}
+		case "throttled":
+			return TestResponse{}, fmt.Errorf("user %s: %w", req.Name, handler.ErrTooManyRequests)
+		}
+		return TestResponse{ID: 1, Name: req.Name, Email: req.Email}, nil
+	})
+	assert.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	client := natsclient.NewClient[TestRequest, TestResponse](nc, "user.create")
+
+	t.Run("success", func(t *testing.T) {
+		result, err := client(context.Background(), TestRequest{Name: "Alice", Email: "alice@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, TestResponse{ID: 1, Name: "Alice", Email: "alice@example.com"}, result)
+	})
+
+	t.Run("standard_error", func(t *testing.T) {
+		_, err := client(context.Background(), TestRequest{Name: "exists"})
+
+		assert.ErrorIs(t, err, handler.ErrConflict)
+
+		_, err = client(context.Background(), TestRequest{Name: "throttled"})
+
+		assert.ErrorIs(t, err, handler.ErrTooManyRequests)
+	})
+
+	t.Run("error_envelope", func(t *testing.T) {
+		data, _ := json.Marshal(TestRequest{Name: "invalid"})
+		reply, err := nc.Request("user.create", data, time.Second)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "400", reply.Header.Get(ErrorCodeHeader))
+		assert.Equal(t, "validation failed", reply.Header.Get(ErrorHeader))
+		assert.JSONEq(t, `{"error":{"message":"validation failed","details":[{"field":"email","issue":"required"}]}}`, string(reply.Data))
+	})
+
+	t.Run("decode_error", func(t *testing.T) {
+		reply, err := nc.Request("user.create", []byte("{invalid"), time.Second)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "400", reply.Header.Get(ErrorCodeHeader))
+	})
+}
+
+// TestSubscribeHandlerPanic tests that a panicking handler is answered with a 500 error envelope
+func TestSubscribeHandlerPanic(t *testing.T) {
+	nc := newTestConn(t)
+	sub, err := SubscribeHandler(nc, "user.panic", func(ctx context.Context, req TestRequest) (TestResponse, error) {
+		if req.Name == "panic" {
+			panic("boom")
+		}
+		return TestResponse{Name: req.Name}, nil
+	})
+	assert.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	data, _ := json.Marshal(TestRequest{Name: "panic"})
+	reply, err := nc.Request("user.panic", data, time.Second)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "500", reply.Header.Get(ErrorCodeHeader))
+	assert.Equal(t, "handler panicked: boom", reply.Header.Get(ErrorHeader))
+	assert.JSONEq(t, `{"error":"handler panicked: boom"}`, string(reply.Data))
+
+	// 订阅在 panic 后仍可继续处理请求
+	data, _ = json.Marshal(TestRequest{Name: "Alice"})
+	reply, err = nc.Request("user.panic", data, time.Second)
+	assert.NoError(t, err)
+	assert.Empty(t, reply.Header.Get(ErrorCodeHeader))
+	assert.JSONEq(t, `{"id":0,"name":"Alice","email":""}`, string(reply.Data))
+}
+
+// TestHandlerVariants tests the SubscribeGetter, SubscribeConsumer and SubscribeAction functionality
+func TestHandlerVariants(t *testing.T) {
+	nc := newTestConn(t)
+	consumed := make(chan TestRequest, 1)
+
+	_, err := SubscribeGetter(nc, "user.health", func(ctx context.Context) (TestResponse, error) {
+		return TestResponse{Name: "ok"}, nil
+	})
+	assert.NoError(t, err)
+	_, err = SubscribeConsumer(nc, "user.delete", func(ctx context.Context, req TestRequest) error {
+		consumed <- req
+		return nil
+	})
+	assert.NoError(t, err)
+	_, err = SubscribeAction(nc, "task.trigger", func(ctx context.Context) error {
+		return errors.New("trigger failed")
+	})
+	assert.NoError(t, err)
+
+	t.Run("getter", func(t *testing.T) {
+		result, err := natsclient.NewGetter[TestResponse](nc, "user.health")(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", result.Name)
+	})
+
+	t.Run("consumer", func(t *testing.T) {
+		err := natsclient.NewConsumer[TestRequest](nc, "user.delete")(context.Background(), TestRequest{Name: "Bob"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bob", (<-consumed).Name)
+	})
+
+	t.Run("action_error", func(t *testing.T) {
+		err := natsclient.NewAction(nc, "task.trigger")(context.Background())
+
+		assert.EqualError(t, err, "service error 500: trigger failed")
+	})
+}
+
+// TestCustomOptions tests the custom decoder, encoder, error handler and queue group options
+func TestCustomOptions(t *testing.T) {
+	nc := newTestConn(t)
+
+	t.Run("decoder_encoder", func(t *testing.T) {
+		_, err := SubscribeHandler(nc, "echo", func(ctx context.Context, in string) (string, error) {
+			return "echo: " + in, nil
+		},
+			WithDecoder(func(msg *nats.Msg) (any, error) {
+				return string(msg.Data), nil
+			}),
+			WithEncoder(func(reply *nats.Msg, output any) error {
+				reply.Data = []byte(output.(string))
+				return nil
+			}),
+		)
+		assert.NoError(t, err)
+
+		reply, err := nc.Request("echo", []byte("hello"), time.Second)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "echo: hello", string(reply.Data))
+	})
+
+	t.Run("error_handler", func(t *testing.T) {
+		_, err := SubscribeAction(nc, "fail", func(ctx context.Context) error {
+			return errors.New("boom")
+		}, WithErrorHandler(func(reply *nats.Msg, err error) {
+			reply.Data = []byte("custom: " + err.Error())
+		}))
+		assert.NoError(t, err)
+
+		reply, err := nc.Request("fail", nil, time.Second)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "custom: boom", string(reply.Data))
+	})
+
+	t.Run("queue_group", func(t *testing.T) {
+		handled := make(chan string, 2)
+		for _, name := range []string{"a", "b"} {
+			_, err := SubscribeAction(nc, "work", func(ctx context.Context) error {
+				handled <- name
+				return nil
+			}, WithQueueGroup("workers"))
+			assert.NoError(t, err)
+		}
+
+		err := natsclient.NewAction(nc, "work")(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, handled, 1)
+	})
+}
@@ -0,0 +1,101 @@
+package restyclient
+
+import (
+	"context"
+
+	"github.com/zhangzqs/go-typed-rpc/handler"
+	"golang.org/x/sync/errgroup"
+)
+
+// Call 批量请求中的一个调用，由 Prepare/PrepareGetter 将客户端处理器和输入绑定而成
+type Call func(ctx context.Context) (any, error)
+
+// Result 批量请求中单个调用的结果，顺序与传入 Batch 的调用一致
+type Result struct {
+	Value any
+	Err   error
+}
+
+// Prepare 将带输入的客户端处理器（如 NewClient、NewPoster 的返回值）与输入绑定为 Call
+func Prepare[I, O any](h handler.HandlerFunc[I, O], input I) Call {
+	return func(ctx context.Context) (any, error) {
+		return h(ctx, input)
+	}
+}
+
+// PrepareGetter 将无输入的客户端处理器（如 NewGetter 的返回值）包装为 Call
+func PrepareGetter[O any](h handler.GetterHandlerFunc[O]) Call {
+	return func(ctx context.Context) (any, error) {
+		return h(ctx)
+	}
+}
+
+// ResultAs 取出 Result 中类型为 O 的值，调用失败时返回其错误，类型不符时返回 ErrDecoderReturnedWrongType
+func ResultAs[O any](r Result) (O, error) {
+	var zero O
+	if r.Err != nil {
+		return zero, r.Err
+	}
+	v, ok := r.Value.(O)
+	if !ok {
+		return zero, ErrDecoderReturnedWrongType
+	}
+	return v, nil
+}
+
+type BatchOptions struct {
+	concurrency int
+	failFast    bool
+}
+
+type BatchOptionFunc func(*BatchOptions)
+
+// WithBatchConcurrency 限制同时执行的调用数，不大于 0 时不限制
+func WithBatchConcurrency(n int) BatchOptionFunc {
+	return func(opts *BatchOptions) {
+		opts.concurrency = n
+	}
+}
+
+// WithBatchFailFast 任一调用失败时取消其余调用（其 ctx 被取消，尚未开始的调用不再执行），Batch 返回首个错误
+func WithBatchFailFast() BatchOptionFunc {
+	return func(opts *BatchOptions) {
+		opts.failFast = true
+	}
+}
+
+// Batch 并发执行多个互相独立的调用，等待全部结束后按传入顺序返回各自的结果
+// 默认不因单个调用失败而中断，错误记录在对应的 Result.Err 中，返回的 error 为 nil；
+// 设置 WithBatchFailFast 时返回首个错误，被取消或未执行的调用的 Result.Err 为取消错误
+// 适用场景：仪表盘等需要同时拉取多个接口数据的聚合层
+func Batch(ctx context.Context, calls []Call, options ...BatchOptionFunc) ([]Result, error) {
+	var opts BatchOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	results := make([]Result, len(calls))
+	g, gctx := errgroup.WithContext(ctx)
+	if opts.concurrency > 0 {
+		g.SetLimit(opts.concurrency)
+	}
+	for i, call := range calls {
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				results[i].Err = err
+				return nil
+			}
+			callCtx := ctx
+			if opts.failFast {
+				callCtx = gctx
+			}
+			value, err := call(callCtx)
+			results[i] = Result{Value: value, Err: err}
+			if opts.failFast {
+				return err
+			}
+			return nil
+		})
+	}
+	return results, g.Wait()
+}
@@ -0,0 +1,89 @@
+package restyclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"resty.dev/v3"
+)
+
+// TestBatch tests running independent client calls concurrently with a shared error policy
+func TestBatch(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		switch r.URL.Path {
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		case "/slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(2 * time.Second):
+			}
+		default:
+			time.Sleep(20 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"name":"` + r.URL.Path[1:] + `"}`))
+	}))
+	defer server.Close()
+
+	client := resty.New().SetBaseURL(server.URL)
+	users := NewGetter[TestResponse](client, http.MethodGet, "/users")
+	orders := NewGetter[TestResponse](client, http.MethodGet, "/orders")
+	stats := NewClient[struct{}, TestResponse](client, http.MethodGet, "/stats")
+	fail := NewGetter[TestResponse](client, http.MethodGet, "/fail")
+	slow := NewGetter[TestResponse](client, http.MethodGet, "/slow")
+
+	t.Run("all_results_in_order", func(t *testing.T) {
+		maxInFlight.Store(0)
+		results, err := Batch(context.Background(), []Call{
+			PrepareGetter(users),
+			PrepareGetter(orders),
+			Prepare(stats, struct{}{}),
+			PrepareGetter(fail),
+		}, WithBatchConcurrency(2))
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 4)
+		for i, name := range []string{"users", "orders", "stats"} {
+			resp, err := ResultAs[TestResponse](results[i])
+			assert.NoError(t, err)
+			assert.Equal(t, name, resp.Name)
+		}
+		assert.EqualError(t, results[3].Err, "500 Internal Server Error")
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	})
+
+	t.Run("fail_fast", func(t *testing.T) {
+		start := time.Now()
+		results, err := Batch(context.Background(), []Call{
+			PrepareGetter(slow),
+			PrepareGetter(fail),
+		}, WithBatchFailFast())
+
+		assert.EqualError(t, err, "500 Internal Server Error")
+		assert.Error(t, results[0].Err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("wrong_type", func(t *testing.T) {
+		_, err := ResultAs[string](Result{Value: TestResponse{}})
+
+		assert.ErrorIs(t, err, ErrDecoderReturnedWrongType)
+	})
+}
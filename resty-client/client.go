@@ -2,11 +2,19 @@ package restyclient
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/zhangzqs/go-typed-rpc/handler"
 	"resty.dev/v3"
@@ -18,14 +26,27 @@ type ResponseDecoderFunc func(resp *resty.Response) (any, error)
 
 type ErrorHandlerFunc func(resp *resty.Response, err error) error
 
+// RequestSignerFunc 请求签名函数，body 为最终发送的请求体字节
+type RequestSignerFunc func(req *resty.Request, body []byte) error
+
 // 错误定义
 var ErrEncoderReceivedWrongType = errors.New("encoder received wrong type")
 var ErrDecoderReturnedWrongType = errors.New("decoder returned wrong type")
+var ErrRequestCanceled = errors.New("request canceled")
+var ErrRequestTimeout = errors.New("request timeout")
 
 type ClientOptions struct {
 	encoder      RequestEncoderFunc
 	decoder      ResponseDecoderFunc
 	errorHandler ErrorHandlerFunc
+	signer       RequestSignerFunc
+
+	connResetRetries int
+	deadlineHeader   string
+	tracing          *handler.TracingConfig
+
+	jsonMarshal   func(v any) ([]byte, error)
+	jsonUnmarshal func(data []byte, v any) error
 }
 
 type ClientOptionFunc func(*ClientOptions)
@@ -48,6 +69,73 @@ func WithErrorHandler(errHandler ErrorHandlerFunc) ClientOptionFunc {
 	}
 }
 
+// WithJSONCodec 设置默认编码器和默认解码器使用的 JSON 序列化函数，可替换为 sonic、jsoniter 等实现
+// 请求体由 marshal 序列化后以字节形式发送；未通过 WithDecoder 自定义解码器时，响应体使用 unmarshal 反序列化
+func WithJSONCodec(marshal func(v any) ([]byte, error), unmarshal func(data []byte, v any) error) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.jsonMarshal = marshal
+		opts.jsonUnmarshal = unmarshal
+	}
+}
+
+// WithRequestSigner 设置请求签名函数
+// 在编码器之后执行，请求体会先被序列化为字节并以字节形式发送，保证签名覆盖的正是实际发送的内容
+func WithRequestSigner(signer RequestSignerFunc) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.signer = signer
+	}
+}
+
+// WithDeadlinePropagation 将 ctx 的剩余时间写入 headerName 指定的请求头，便于下游服务按预算处理
+// 值为剩余毫秒数（十进制整数，已过期时为 0）；ctx 没有截止时间时不设置该请求头
+func WithDeadlinePropagation(headerName string) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.deadlineHeader = headerName
+	}
+}
+
+// NewHMACSigner 创建 HMAC-SHA256 请求签名函数
+// 将请求体的十六进制摘要写入 header 指定的请求头，与 ginserver.WithHMACVerification 的校验方式一致
+func NewHMACSigner(secret []byte, header string) RequestSignerFunc {
+	return func(req *resty.Request, body []byte) error {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		req.SetHeader(header, hex.EncodeToString(mac.Sum(nil)))
+		return nil
+	}
+}
+
+// serializeRequestBody 将编码器设置的请求体序列化为字节并替换原请求体
+// 结构体、map、切片等使用 marshal 按 JSON 序列化，与 resty 的默认行为一致
+func serializeRequestBody(req *resty.Request, marshal func(v any) ([]byte, error)) ([]byte, error) {
+	var data []byte
+	switch body := req.Body.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		data = body
+	case string:
+		data = []byte(body)
+	case io.Reader:
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	default:
+		b, err := marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+		if req.Header.Get("Content-Type") == "" {
+			req.SetHeader("Content-Type", "application/json")
+		}
+	}
+	req.SetBody(data)
+	return data, nil
+}
+
 // DefaultRequestEncoder 默认请求编码器
 // 智能处理多种请求参数：PathParams、QueryParams、Headers、Body
 // 支持标签：
@@ -61,6 +149,12 @@ func DefaultRequestEncoder[I any]() RequestEncoderFunc {
 			return nil
 		}
 
+		// 流式请求体（如 *os.File）直接作为 body，不按结构体字段展开
+		if _, ok := input.(io.Reader); ok {
+			req.SetBody(input)
+			return nil
+		}
+
 		v := reflect.ValueOf(input)
 		// 处理指针类型
 		if v.Kind() == reflect.Ptr {
@@ -78,11 +172,16 @@ func DefaultRequestEncoder[I any]() RequestEncoderFunc {
 		}
 
 		t := v.Type()
+		// 没有导出字段的结构体（如 NewGetter/NewAction 使用的 struct{}）不携带任何参数，发送空请求
+		if !hasExportedField(t) {
+			return nil
+		}
 		pathParams := make(map[string]string)
 		queryParams := make(map[string]string)
 		headers := make(map[string]string)
 		bodyFields := make(map[string]any)
 		hasBodyTag := false
+		hasQueryMap := false
 
 		// 遍历所有字段
 		for i := 0; i < v.NumField(); i++ {
@@ -107,13 +206,19 @@ func DefaultRequestEncoder[I any]() RequestEncoderFunc {
 				continue
 			}
 
-			// 2. 检查 query 或 form 标签
-			if queryTag := field.Tag.Get("query"); queryTag != "" {
-				queryParams[queryTag] = strValue
-				continue
+			// 2. 检查 query 或 form 标签，map 类型的字段按键展开为多个查询参数
+			queryTag := field.Tag.Get("query")
+			if queryTag == "" {
+				queryTag = field.Tag.Get("form")
 			}
-			if formTag := field.Tag.Get("form"); formTag != "" {
-				queryParams[formTag] = strValue
+			if queryTag != "" {
+				if mapValue := reflect.Indirect(fieldValue); mapValue.Kind() == reflect.Map {
+					hasQueryMap = true
+					name, style, _ := strings.Cut(queryTag, ",")
+					encodeQueryMap(queryParams, name, style, mapValue)
+					continue
+				}
+				queryParams[queryTag] = strValue
 				continue
 			}
 
@@ -152,7 +257,7 @@ func DefaultRequestEncoder[I any]() RequestEncoderFunc {
 		// 设置请求体
 		if hasBodyTag && len(bodyFields) > 0 {
 			req.SetBody(bodyFields)
-		} else if !hasBodyTag && len(pathParams) == 0 && len(queryParams) == 0 && len(headers) == 0 {
+		} else if !hasBodyTag && !hasQueryMap && len(pathParams) == 0 && len(queryParams) == 0 && len(headers) == 0 {
 			// 如果没有任何特殊标签，整个对象作为 body
 			req.SetBody(input)
 		}
@@ -161,18 +266,54 @@ func DefaultRequestEncoder[I any]() RequestEncoderFunc {
 	}
 }
 
+// encodeQueryMap 将 map 字段展开为查询参数，键和值均按 fmt 的 %v 格式化
+// style 为 dot 时生成 name.key=value，否则生成 name[key]=value
+// 例如 `query:"filter"` 的 map[string]string{"status": "active"} 生成 filter[status]=active
+func encodeQueryMap(params map[string]string, name, style string, m reflect.Value) {
+	iter := m.MapRange()
+	for iter.Next() {
+		key := fmt.Sprintf("%v", iter.Key().Interface())
+		if style == "dot" {
+			key = name + "." + key
+		} else {
+			key = name + "[" + key + "]"
+		}
+		params[key] = fmt.Sprintf("%v", iter.Value().Interface())
+	}
+}
+
+// hasExportedField 判断结构体是否包含导出字段
+func hasExportedField(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
 // DefaultResponseDecoder 默认响应解码器
 // 自动将响应体反序列化为目标类型
 func DefaultResponseDecoder[O any]() ResponseDecoderFunc {
+	return defaultResponseDecoder[O](json.Unmarshal)
+}
+
+// defaultResponseDecoder 使用 unmarshal 反序列化响应体的默认响应解码器
+func defaultResponseDecoder[O any](unmarshal func(data []byte, v any) error) ResponseDecoderFunc {
 	return func(resp *resty.Response) (any, error) {
 		var result O
+		// 204/205/304 响应按规范没有响应体，直接返回零值
+		switch resp.StatusCode() {
+		case http.StatusNoContent, http.StatusResetContent, http.StatusNotModified:
+			return result, nil
+		}
 		// resty v3: resp.Bytes() 替代了 v2 的 resp.Body()
 		bodyBytes := resp.Bytes()
 		if len(bodyBytes) == 0 {
 			// 空响应体，返回零值
 			return result, nil
 		}
-		if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		if err := unmarshal(bodyBytes, &result); err != nil {
 			return nil, err
 		}
 		return result, nil
@@ -180,11 +321,11 @@ func DefaultResponseDecoder[O any]() ResponseDecoderFunc {
 }
 
 // DefaultErrorHandler 默认错误处理器
-// 检查 HTTP 状态码和错误
+// 检查 HTTP 状态码和错误，context 取消和超时会被归类为 ErrRequestCanceled/ErrRequestTimeout
 func DefaultErrorHandler() ErrorHandlerFunc {
 	return func(resp *resty.Response, err error) error {
 		if err != nil {
-			return err
+			return ClassifyError(err)
 		}
 		if resp.IsError() {
 			return errors.New(resp.Status())
@@ -193,17 +334,44 @@ func DefaultErrorHandler() ErrorHandlerFunc {
 	}
 }
 
+// ClassifyError 将请求错误归类为可区分的错误
+// context.Canceled 归类为 ErrRequestCanceled；context.DeadlineExceeded 和网络超时归类为 ErrRequestTimeout
+// 归类后的错误同时包装了原始错误，仍可通过 errors.Is/errors.As 判断
+func ClassifyError(err error) error {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrRequestCanceled), errors.Is(err, ErrRequestTimeout):
+		return err
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("%w: %w", ErrRequestCanceled, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrRequestTimeout, err)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return fmt.Errorf("%w: %w", ErrRequestTimeout, err)
+	default:
+		return err
+	}
+}
+
 func mergeOptions[I, O any](
 	options ...ClientOptionFunc,
 ) *ClientOptions {
 	opts := ClientOptions{
 		encoder:      DefaultRequestEncoder[I](),
-		decoder:      DefaultResponseDecoder[O](),
 		errorHandler: DefaultErrorHandler(),
 	}
 	for _, opt := range options {
 		opt(&opts)
 	}
+	if opts.decoder == nil {
+		unmarshal := opts.jsonUnmarshal
+		if unmarshal == nil {
+			unmarshal = json.Unmarshal
+		}
+		opts.decoder = defaultResponseDecoder[O](unmarshal)
+	}
 	return &opts
 }
 
@@ -214,6 +382,46 @@ type Client[I, O any] struct {
 	options     *ClientOptions
 }
 
+// newRequest 创建并编码一次请求，重试时每次重新创建以保证请求体可以再次发送
+func newRequest(restyClient *resty.Client, opts *ClientOptions, ctx context.Context, input any) (*resty.Request, error) {
+	req := restyClient.R().SetContext(ctx)
+
+	// 传播截止时间，每次重试都按当前剩余时间重新计算
+	if opts.deadlineHeader != "" {
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining := max(time.Until(deadline).Milliseconds(), 0)
+			req.SetHeader(opts.deadlineHeader, strconv.FormatInt(remaining, 10))
+		}
+	}
+
+	if opts.tracing != nil {
+		req.SetHeader(opts.tracing.HeaderName(), handler.RequestIDFromContext(ctx))
+	}
+
+	// 编码请求
+	if err := opts.encoder(req, input); err != nil {
+		return nil, err
+	}
+
+	// 使用自定义 JSON 序列化或需要签名时，先将请求体序列化为字节
+	if opts.jsonMarshal != nil || opts.signer != nil {
+		marshal := opts.jsonMarshal
+		if marshal == nil {
+			marshal = json.Marshal
+		}
+		body, err := serializeRequestBody(req, marshal)
+		if err != nil {
+			return nil, err
+		}
+		if opts.signer != nil {
+			if err := opts.signer(req, body); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return req, nil
+}
+
 // NewClient 创建一个通用的 HTTP 客户端
 // 支持完全自定义的输入输出类型
 func NewClient[I, O any](
@@ -226,16 +434,27 @@ func NewClient[I, O any](
 
 	return func(ctx context.Context, input I) (O, error) {
 		var zero O
-
-		req := restyClient.R().SetContext(ctx)
-
-		// 编码请求
-		if err := opts.encoder(req, input); err != nil {
-			return zero, err
+		if opts.tracing != nil {
+			ctx = tracingContext(ctx)
 		}
 
-		// 发送请求
-		resp, err := req.Execute(method, url)
+		// 发送请求，连接被重置时按 WithConnResetRetry 重试幂等请求
+		start := time.Now()
+		var resp *resty.Response
+		var err error
+		for attempt := 0; ; attempt++ {
+			var req *resty.Request
+			if req, err = newRequest(restyClient, opts, ctx, input); err != nil {
+				return zero, err
+			}
+			resp, err = req.Execute(method, url)
+			if attempt >= opts.connResetRetries || !canRetryConnReset(method, input, err) {
+				break
+			}
+		}
+		if opts.tracing != nil && opts.tracing.Logger != nil {
+			logClientRequest(ctx, opts.tracing, method, url, resp, err, start)
+		}
 
 		// 错误处理
 		if err := opts.errorHandler(resp, err); err != nil {
@@ -301,3 +520,43 @@ func NewConsumer[I any](
 		return err
 	}
 }
+
+// NewPoster 创建使用 POST 方法的客户端处理器
+// 适用场景：创建资源等需要请求体并返回数据的场景
+func NewPoster[I, O any](
+	restyClient *resty.Client,
+	url string,
+	options ...ClientOptionFunc,
+) handler.HandlerFunc[I, O] {
+	return NewClient[I, O](restyClient, http.MethodPost, url, options...)
+}
+
+// NewPutter 创建使用 PUT 方法的客户端处理器
+// 适用场景：整体替换资源等场景
+func NewPutter[I, O any](
+	restyClient *resty.Client,
+	url string,
+	options ...ClientOptionFunc,
+) handler.HandlerFunc[I, O] {
+	return NewClient[I, O](restyClient, http.MethodPut, url, options...)
+}
+
+// NewPatcher 创建使用 PATCH 方法的客户端处理器
+// 适用场景：部分更新资源等场景
+func NewPatcher[I, O any](
+	restyClient *resty.Client,
+	url string,
+	options ...ClientOptionFunc,
+) handler.HandlerFunc[I, O] {
+	return NewClient[I, O](restyClient, http.MethodPatch, url, options...)
+}
+
+// NewDeleter 创建使用 DELETE 方法的客户端处理器
+// 适用场景：删除资源等不需要返回数据的场景
+func NewDeleter[I any](
+	restyClient *resty.Client,
+	url string,
+	options ...ClientOptionFunc,
+) handler.ConsumerHandlerFunc[I] {
+	return NewConsumer[I](restyClient, http.MethodDelete, url, options...)
+}
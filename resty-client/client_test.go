@@ -2,11 +2,17 @@ package restyclient
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"resty.dev/v3"
@@ -132,6 +138,49 @@ func TestNewGetter(t *testing.T) {
 	})
 }
 
+// TestEmptyInputSendsEmptyRequest tests that getters and nil pointer inputs send no body or params
+func TestEmptyInputSendsEmptyRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Empty(t, body)
+		assert.Empty(t, r.URL.RawQuery)
+		assert.Empty(t, r.Header.Get("Content-Type"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(HealthResponse{Status: "ok"})
+	}))
+	defer server.Close()
+
+	client := resty.New()
+
+	t.Run("getter", func(t *testing.T) {
+		handler := NewGetter[HealthResponse](client, http.MethodGet, server.URL+"/health")
+
+		result, err := handler(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", result.Status)
+	})
+
+	t.Run("nil_pointer_input", func(t *testing.T) {
+		handler := NewClient[*TestRequest, HealthResponse](client, http.MethodGet, server.URL+"/health")
+
+		result, err := handler(context.Background(), nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", result.Status)
+	})
+
+	t.Run("action", func(t *testing.T) {
+		handler := NewAction(client, http.MethodPost, server.URL+"/tasks")
+
+		err := handler(context.Background())
+
+		assert.NoError(t, err)
+	})
+}
+
 // TestNewPoster tests the NewPoster functionality
 func TestNewPoster(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
@@ -146,9 +195,9 @@ func TestNewPoster(t *testing.T) {
 		defer server.Close()
 
 		client := resty.New()
-		handler := NewConsumer[TestRequest](client, http.MethodPost, server.URL+"/users")
+		handler := NewPoster[TestRequest, struct{}](client, server.URL+"/users")
 
-		err := handler(context.Background(), TestRequest{
+		_, err := handler(context.Background(), TestRequest{
 			Name:  "Alice",
 			Email: "alice@example.com",
 		})
@@ -163,9 +212,9 @@ func TestNewPoster(t *testing.T) {
 		defer server.Close()
 
 		client := resty.New()
-		handler := NewConsumer[TestRequest](client, http.MethodPost, server.URL+"/users")
+		handler := NewPoster[TestRequest, struct{}](client, server.URL+"/users")
 
-		err := handler(context.Background(), TestRequest{
+		_, err := handler(context.Background(), TestRequest{
 			Name:  "Alice",
 			Email: "alice@example.com",
 		})
@@ -187,9 +236,9 @@ func TestNewPutter(t *testing.T) {
 		defer server.Close()
 
 		client := resty.New()
-		handler := NewConsumer[TestRequest](client, http.MethodPut, server.URL+"/users/1")
+		handler := NewPutter[TestRequest, struct{}](client, server.URL+"/users/1")
 
-		err := handler(context.Background(), TestRequest{
+		_, err := handler(context.Background(), TestRequest{
 			Name:  "Alice",
 			Email: "alice@example.com",
 		})
@@ -208,9 +257,9 @@ func TestNewDeleter(t *testing.T) {
 		defer server.Close()
 
 		client := resty.New()
-		handler := NewAction(client, http.MethodDelete, server.URL+"/users/1")
+		handler := NewDeleter[struct{}](client, server.URL+"/users/1")
 
-		err := handler(context.Background())
+		err := handler(context.Background(), struct{}{})
 
 		assert.NoError(t, err)
 	})
@@ -222,14 +271,34 @@ func TestNewDeleter(t *testing.T) {
 		defer server.Close()
 
 		client := resty.New()
-		handler := NewAction(client, http.MethodDelete, server.URL+"/users/999")
+		handler := NewDeleter[struct{}](client, server.URL+"/users/999")
 
-		err := handler(context.Background())
+		err := handler(context.Background(), struct{}{})
 
 		assert.Error(t, err)
 	})
 }
 
+// TestNewPatcher tests the NewPatcher functionality
+func TestNewPatcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		var req TestRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TestResponse{ID: 1, Name: req.Name})
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	handler := NewPatcher[TestRequest, TestResponse](client, server.URL+"/users/1")
+
+	result, err := handler(context.Background(), TestRequest{Name: "Bob"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bob", result.Name)
+}
+
 // TestNewAction tests the NewAction functionality
 func TestNewAction(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
@@ -475,6 +544,195 @@ func TestMergeOptions(t *testing.T) {
 	assert.NotNil(t, opts.errorHandler)
 }
 
+// TestDefaultResponseDecoderEmptyStatus tests decoding bodiless status codes into the zero value
+func TestDefaultResponseDecoderEmptyStatus(t *testing.T) {
+	for _, status := range []int{http.StatusNoContent, http.StatusResetContent, http.StatusNotModified} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			client := resty.New()
+
+			count, err := NewClient[TestRequest, int](client, http.MethodPost, server.URL+"/users")(
+				context.Background(), TestRequest{Name: "Alice"},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, count)
+
+			names, err := NewGetter[[]string](client, http.MethodGet, server.URL+"/users")(context.Background())
+			assert.NoError(t, err)
+			assert.Nil(t, names)
+		})
+	}
+}
+
+// TestDefaultErrorHandlerContextErrors tests classifying cancellation and timeout errors
+func TestDefaultErrorHandlerContextErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	handler := NewGetter[HealthResponse](client, http.MethodGet, server.URL+"/health")
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+		}()
+
+		_, err := handler(ctx)
+
+		assert.ErrorIs(t, err, ErrRequestCanceled)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrRequestTimeout)
+	})
+
+	t.Run("deadline_exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := handler(ctx)
+
+		assert.ErrorIs(t, err, ErrRequestTimeout)
+		assert.NotErrorIs(t, err, ErrRequestCanceled)
+	})
+
+	t.Run("client_timeout", func(t *testing.T) {
+		timeoutClient := resty.New().SetTimeout(20 * time.Millisecond)
+		_, err := NewGetter[HealthResponse](timeoutClient, http.MethodGet, server.URL+"/health")(context.Background())
+
+		assert.ErrorIs(t, err, ErrRequestTimeout)
+	})
+
+	t.Run("server_error_unchanged", func(t *testing.T) {
+		errServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer errServer.Close()
+
+		_, err := NewGetter[HealthResponse](client, http.MethodGet, errServer.URL+"/health")(context.Background())
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrRequestCanceled)
+		assert.NotErrorIs(t, err, ErrRequestTimeout)
+	})
+}
+
+// TestWithRequestSigner tests signing the serialized request body
+func TestWithRequestSigner(t *testing.T) {
+	secret := []byte("top-secret")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(body)
+		if r.Header.Get("X-Signature") != hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req TestRequest
+		assert.NoError(t, json.Unmarshal(body, &req))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TestResponse{ID: 1, Name: req.Name, Email: req.Email})
+	}))
+	defer server.Close()
+
+	client := resty.New()
+
+	t.Run("signed", func(t *testing.T) {
+		handler := NewClient[TestRequest, TestResponse](
+			client,
+			http.MethodPost,
+			server.URL+"/users",
+			WithRequestSigner(NewHMACSigner(secret, "X-Signature")),
+		)
+
+		result, err := handler(context.Background(), TestRequest{Name: "Alice", Email: "alice@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", result.Name)
+	})
+
+	t.Run("wrong_secret", func(t *testing.T) {
+		handler := NewClient[TestRequest, TestResponse](
+			client,
+			http.MethodPost,
+			server.URL+"/users",
+			WithRequestSigner(NewHMACSigner([]byte("wrong"), "X-Signature")),
+		)
+
+		_, err := handler(context.Background(), TestRequest{Name: "Alice", Email: "alice@example.com"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("signer_error", func(t *testing.T) {
+		signErr := errors.New("sign failed")
+		handler := NewClient[TestRequest, TestResponse](
+			client,
+			http.MethodPost,
+			server.URL+"/users",
+			WithRequestSigner(func(req *resty.Request, body []byte) error {
+				assert.JSONEq(t, `{"name":"Alice","email":"alice@example.com"}`, string(body))
+				return signErr
+			}),
+		)
+
+		_, err := handler(context.Background(), TestRequest{Name: "Alice", Email: "alice@example.com"})
+
+		assert.ErrorIs(t, err, signErr)
+	})
+}
+
+// TestWithJSONCodec tests plugging a custom JSON codec into the default encoder and decoder
+func TestWithJSONCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req TestRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TestResponse{ID: 1, Name: req.Name, Email: req.Email})
+	}))
+	defer server.Close()
+
+	var marshaled, unmarshaled int
+	handler := NewClient[TestRequest, TestResponse](
+		resty.New(),
+		http.MethodPost,
+		server.URL+"/users",
+		WithJSONCodec(
+			func(v any) ([]byte, error) {
+				marshaled++
+				return json.Marshal(v)
+			},
+			func(data []byte, v any) error {
+				unmarshaled++
+				return json.Unmarshal(data, v)
+			},
+		),
+	)
+
+	result, err := handler(context.Background(), TestRequest{Name: "Alice", Email: "alice@example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, TestResponse{ID: 1, Name: "Alice", Email: "alice@example.com"}, result)
+	assert.Equal(t, 1, marshaled)
+	assert.Equal(t, 1, unmarshaled)
+}
+
 // BenchmarkNewClient benchmarks the NewClient function
 func BenchmarkNewClient(b *testing.B) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -501,3 +759,93 @@ func BenchmarkNewClient(b *testing.B) {
 		})
 	}
 }
+
+// TestWithDeadlinePropagation tests propagating the remaining context budget as a header
+func TestWithDeadlinePropagation(t *testing.T) {
+	var received string
+	var present bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, present = r.Header["X-Request-Deadline"]
+		received = r.Header.Get("X-Request-Deadline")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := resty.New().SetBaseURL(server.URL)
+	action := NewAction(client, http.MethodPost, "/tasks", WithDeadlinePropagation("X-Request-Deadline"))
+
+	t.Run("with_deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		err := action(ctx)
+
+		assert.NoError(t, err)
+		assert.True(t, present)
+		ms, err := strconv.ParseInt(received, 10, 64)
+		assert.NoError(t, err)
+		assert.Greater(t, ms, int64(1000))
+		assert.LessOrEqual(t, ms, int64(2000))
+	})
+
+	t.Run("without_deadline", func(t *testing.T) {
+		err := action(context.Background())
+
+		assert.NoError(t, err)
+		assert.False(t, present)
+	})
+}
+
+// TestQueryMapEncoding tests expanding map fields into bracket or dot style query parameters
+func TestQueryMapEncoding(t *testing.T) {
+	type SearchRequest struct {
+		Filter map[string]string `query:"filter"`
+		Range  map[string]int    `query:"range,dot"`
+		Page   int               `query:"page"`
+	}
+
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := resty.New()
+	search := NewConsumer[SearchRequest](client, http.MethodGet, server.URL+"/users")
+
+	t.Run("expand_maps", func(t *testing.T) {
+		err := search(context.Background(), SearchRequest{
+			Filter: map[string]string{"status": "active", "role": "admin"},
+			Range:  map[string]int{"min": 1, "max": 10},
+			Page:   2,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"filter[status]": {"active"},
+			"filter[role]":   {"admin"},
+			"range.min":      {"1"},
+			"range.max":      {"10"},
+			"page":           {"2"},
+		}, query)
+	})
+
+	t.Run("only_empty_map", func(t *testing.T) {
+		type FilterOnly struct {
+			Filter map[string]string `query:"filter"`
+		}
+		var body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		err := NewConsumer[FilterOnly](client, http.MethodGet, server.URL)(context.Background(), FilterOnly{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, body)
+	})
+}
@@ -0,0 +1,70 @@
+package restyclient
+
+import (
+	"io"
+	"net/http"
+	"os"
+
+	"resty.dev/v3"
+)
+
+// sizer 可提供剩余字节数的请求体
+type sizer interface {
+	Size() int64
+}
+
+// sizedReader 附带已知大小的 io.Reader
+type sizedReader struct {
+	io.Reader
+	size int64
+}
+
+func (r *sizedReader) Size() int64 {
+	return r.size
+}
+
+// NewSizedReader 为大小已知的 io.Reader 附带长度信息
+// 配合 ContentLengthMiddleware 使用时，上传该请求体会发送 Content-Length 而不是分块传输
+func NewSizedReader(r io.Reader, size int64) io.Reader {
+	return &sizedReader{Reader: r, size: size}
+}
+
+// ContentLengthMiddleware 为大小已知的流式请求体设置 Content-Length
+// net/http 只能识别 *bytes.Buffer、*bytes.Reader 和 *strings.Reader 的长度，其余 io.Reader 默认使用分块传输；
+// 该中间件为 *os.File 以及 NewSizedReader 等实现了 Size() int64 的请求体补充长度，便于服务端限制大小和展示进度
+// 需要放在 resty.PrepareRequestMiddleware 之后：
+//
+//	client.SetRequestMiddlewares(resty.PrepareRequestMiddleware, restyclient.ContentLengthMiddleware)
+func ContentLengthMiddleware(_ *resty.Client, req *resty.Request) error {
+	raw := req.RawRequest
+	if raw == nil || raw.Body == nil || raw.Body == http.NoBody || raw.ContentLength > 0 {
+		return nil
+	}
+	if size, ok := bodySize(req.Body); ok {
+		raw.ContentLength = size
+		if size == 0 {
+			raw.Body = http.NoBody
+		}
+	}
+	return nil
+}
+
+// bodySize 返回请求体剩余的字节数，无法确定时返回 false
+func bodySize(body any) (int64, bool) {
+	switch b := body.(type) {
+	case sizer:
+		return b.Size(), true
+	case *os.File:
+		info, err := b.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+		offset, err := b.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		return info.Size() - offset, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,70 @@
+package restyclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"resty.dev/v3"
+)
+
+// TestContentLengthMiddleware tests sending Content-Length for streamed bodies of known size
+func TestContentLengthMiddleware(t *testing.T) {
+	type received struct {
+		contentLength    int64
+		transferEncoding []string
+		body             string
+	}
+	var got received
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = received{contentLength: r.ContentLength, transferEncoding: r.TransferEncoding, body: string(body)}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := resty.New().SetRequestMiddlewares(resty.PrepareRequestMiddleware, ContentLengthMiddleware)
+	upload := NewConsumer[io.Reader](client, http.MethodPut, server.URL+"/files")
+
+	t.Run("sized_reader", func(t *testing.T) {
+		// io.MultiReader 无法被 net/http 识别长度
+		reader := io.MultiReader(strings.NewReader("hello "), strings.NewReader("world"))
+
+		err := upload(context.Background(), NewSizedReader(reader, 11))
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(11), got.contentLength)
+		assert.Empty(t, got.transferEncoding)
+		assert.Equal(t, "hello world", got.body)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "upload.txt")
+		assert.NoError(t, os.WriteFile(path, []byte("file content"), 0o600))
+		f, err := os.Open(path)
+		assert.NoError(t, err)
+		defer f.Close()
+
+		err = upload(context.Background(), f)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len("file content")), got.contentLength)
+		assert.Equal(t, "file content", got.body)
+	})
+
+	t.Run("unknown_size", func(t *testing.T) {
+		reader := io.MultiReader(strings.NewReader("chunked"))
+
+		err := upload(context.Background(), reader)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-1), got.contentLength)
+		assert.Equal(t, []string{"chunked"}, got.transferEncoding)
+	})
+}
@@ -0,0 +1,79 @@
+package restyclient
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"resty.dev/v3"
+)
+
+// DefaultEnvelopeDataField 信封响应中数据字段的默认名称
+const DefaultEnvelopeDataField = "data"
+
+// EnvelopeError 信封响应的 code 不为 0 时返回的错误
+type EnvelopeError struct {
+	Code    int64  // 信封中的 code
+	Message string // 信封中的 message（或 msg）
+}
+
+func (e *EnvelopeError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("envelope error: code %d", e.Code)
+	}
+	return fmt.Sprintf("envelope error: code %d: %s", e.Code, e.Message)
+}
+
+// WithEnvelopeDecoder 使用信封响应解码器，适用于返回 {"code":0,"data":{...}} 的接口
+// 将 dataField 字段（为空时使用 DefaultEnvelopeDataField）的内容反序列化为 O；code 不为 0 时返回 *EnvelopeError，
+// 错误信息取自 message 或 msg 字段；设置了 WithJSONCodec 时使用其反序列化函数
+func WithEnvelopeDecoder[O any](dataField string) ClientOptionFunc {
+	if dataField == "" {
+		dataField = DefaultEnvelopeDataField
+	}
+	return func(opts *ClientOptions) {
+		opts.decoder = func(resp *resty.Response) (any, error) {
+			unmarshal := opts.jsonUnmarshal
+			if unmarshal == nil {
+				unmarshal = json.Unmarshal
+			}
+			return decodeEnvelope[O](resp.Bytes(), dataField, unmarshal)
+		}
+	}
+}
+
+// decodeEnvelope 解析信封并将数据字段反序列化为 O，空响应体返回零值
+func decodeEnvelope[O any](body []byte, dataField string, unmarshal func(data []byte, v any) error) (any, error) {
+	var result O
+	if len(body) == 0 {
+		return result, nil
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+	if raw, ok := envelope["code"]; ok {
+		var code int64
+		if err := json.Unmarshal(raw, &code); err != nil {
+			return nil, fmt.Errorf("invalid envelope code: %w", err)
+		}
+		if code != 0 {
+			envErr := &EnvelopeError{Code: code}
+			for _, key := range []string{"message", "msg"} {
+				if raw, ok := envelope[key]; ok && json.Unmarshal(raw, &envErr.Message) == nil {
+					break
+				}
+			}
+			return nil, envErr
+		}
+	}
+
+	data, ok := envelope[dataField]
+	if !ok || string(data) == "null" {
+		return result, nil
+	}
+	if err := unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package restyclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"resty.dev/v3"
+)
+
+// TestWithEnvelopeDecoder tests unwrapping the data field of an enveloped response
+func TestWithEnvelopeDecoder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/1":
+			w.Write([]byte(`{"code":0,"data":{"id":1,"name":"Alice","email":"alice@example.com"}}`))
+		case "/users/2":
+			w.Write([]byte(`{"code":40401,"message":"user not found"}`))
+		case "/users/3":
+			w.Write([]byte(`{"code":0,"result":{"id":3,"name":"Carol"}}`))
+		case "/users/4":
+			w.Write([]byte(`{"code":0,"data":null}`))
+		}
+	}))
+	defer server.Close()
+
+	client := resty.New().SetBaseURL(server.URL)
+
+	t.Run("unwrap_data", func(t *testing.T) {
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/1", WithEnvelopeDecoder[TestResponse](""))
+
+		result, err := get(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, TestResponse{ID: 1, Name: "Alice", Email: "alice@example.com"}, result)
+	})
+
+	t.Run("non_zero_code", func(t *testing.T) {
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/2", WithEnvelopeDecoder[TestResponse](""))
+
+		_, err := get(context.Background())
+
+		var envErr *EnvelopeError
+		assert.True(t, errors.As(err, &envErr))
+		assert.Equal(t, int64(40401), envErr.Code)
+		assert.Equal(t, "user not found", envErr.Message)
+	})
+
+	t.Run("custom_data_field", func(t *testing.T) {
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/3", WithEnvelopeDecoder[TestResponse]("result"))
+
+		result, err := get(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Carol", result.Name)
+	})
+
+	t.Run("null_data", func(t *testing.T) {
+		get := NewGetter[*TestResponse](client, http.MethodGet, "/users/4", WithEnvelopeDecoder[*TestResponse](""))
+
+		result, err := get(context.Background())
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+}
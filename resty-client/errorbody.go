@@ -0,0 +1,38 @@
+package restyclient
+
+import (
+	"net/http"
+
+	"resty.dev/v3"
+)
+
+// HTTPResponseError 状态码为 4xx/5xx 时返回的错误，保留原始响应便于记录和排查
+type HTTPResponseError struct {
+	StatusCode int         // 响应状态码
+	Status     string      // 响应状态行，如 "502 Bad Gateway"
+	Header     http.Header // 响应头
+	Body       []byte      // 原始响应体
+}
+
+func (e *HTTPResponseError) Error() string {
+	return e.Status
+}
+
+// WithErrorBodyCapture 状态码为 4xx/5xx 时返回 *HTTPResponseError，携带状态码、响应头和原始响应体，可通过 errors.As 取得
+// 错误信息与 DefaultErrorHandler 相同（响应状态行）；请求错误的处理与 DefaultErrorHandler 一致。该选项会替换错误处理器
+func WithErrorBodyCapture() ClientOptionFunc {
+	return WithErrorHandler(func(resp *resty.Response, err error) error {
+		if err != nil {
+			return ClassifyError(err)
+		}
+		if resp.IsError() {
+			return &HTTPResponseError{
+				StatusCode: resp.StatusCode(),
+				Status:     resp.Status(),
+				Header:     resp.Header(),
+				Body:       resp.Bytes(),
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,52 @@
+package restyclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"resty.dev/v3"
+)
+
+// TestWithErrorBodyCapture tests that error responses keep their status and raw body
+func TestWithErrorBodyCapture(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/1":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":1,"name":"Alice"}`))
+		default:
+			w.Header().Set("X-Upstream", "cache-3")
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("upstream connect error"))
+		}
+	}))
+	defer server.Close()
+
+	client := resty.New().SetBaseURL(server.URL)
+
+	t.Run("success", func(t *testing.T) {
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/1", WithErrorBodyCapture())
+
+		result, err := get(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", result.Name)
+	})
+
+	t.Run("error_body", func(t *testing.T) {
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/2", WithErrorBodyCapture())
+
+		_, err := get(context.Background())
+
+		assert.EqualError(t, err, "502 Bad Gateway")
+		var respErr *HTTPResponseError
+		assert.True(t, errors.As(err, &respErr))
+		assert.Equal(t, http.StatusBadGateway, respErr.StatusCode)
+		assert.Equal(t, "upstream connect error", string(respErr.Body))
+		assert.Equal(t, "cache-3", respErr.Header.Get("X-Upstream"))
+	})
+}
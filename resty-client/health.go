@@ -0,0 +1,53 @@
+package restyclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"resty.dev/v3"
+)
+
+// HealthStatus 健康检查结果
+type HealthStatus struct {
+	Reachable  bool          `json:"reachable"`   // 是否收到了服务端响应
+	Latency    time.Duration `json:"latency"`     // 请求耗时
+	StatusCode int           `json:"status_code"` // HTTP 状态码，不可达时为 0
+}
+
+// Healthy 服务可达且返回 2xx 状态码
+func (s HealthStatus) Healthy() bool {
+	return s.Reachable && s.StatusCode >= 200 && s.StatusCode < 300
+}
+
+// NewHealthChecker 创建健康检查器
+// 基于 NewGetter 发送 GET 请求并记录耗时；非 2xx 响应不视为错误，而是通过 StatusCode 返回
+// 仅当请求无法送达（连接失败、超时等）时返回错误，此时 Reachable 为 false
+func NewHealthChecker(
+	restyClient *resty.Client,
+	url string,
+) func(ctx context.Context) (HealthStatus, error) {
+	getter := NewGetter[int](
+		restyClient,
+		http.MethodGet,
+		url,
+		WithDecoder(func(resp *resty.Response) (any, error) {
+			return resp.StatusCode(), nil
+		}),
+		WithErrorHandler(func(resp *resty.Response, err error) error {
+			return ClassifyError(err)
+		}),
+	)
+
+	return func(ctx context.Context) (HealthStatus, error) {
+		start := time.Now()
+		statusCode, err := getter(ctx)
+		status := HealthStatus{Latency: time.Since(start)}
+		if err != nil {
+			return status, err
+		}
+		status.Reachable = true
+		status.StatusCode = statusCode
+		return status, nil
+	}
+}
@@ -0,0 +1,62 @@
+package restyclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"resty.dev/v3"
+)
+
+// TestNewHealthChecker tests the health checker helper
+func TestNewHealthChecker(t *testing.T) {
+	t.Run("healthy", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			time.Sleep(10 * time.Millisecond)
+			w.Write([]byte(`{"status":"ok"}`))
+		}))
+		defer server.Close()
+
+		check := NewHealthChecker(resty.New(), server.URL+"/health")
+		status, err := check(context.Background())
+
+		assert.NoError(t, err)
+		assert.True(t, status.Reachable)
+		assert.True(t, status.Healthy())
+		assert.Equal(t, http.StatusOK, status.StatusCode)
+		assert.GreaterOrEqual(t, status.Latency, 10*time.Millisecond)
+	})
+
+	t.Run("unhealthy_status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("not json"))
+		}))
+		defer server.Close()
+
+		check := NewHealthChecker(resty.New(), server.URL+"/health")
+		status, err := check(context.Background())
+
+		assert.NoError(t, err)
+		assert.True(t, status.Reachable)
+		assert.False(t, status.Healthy())
+		assert.Equal(t, http.StatusServiceUnavailable, status.StatusCode)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := server.URL
+		server.Close()
+
+		check := NewHealthChecker(resty.New(), url+"/health")
+		status, err := check(context.Background())
+
+		assert.Error(t, err)
+		assert.False(t, status.Reachable)
+		assert.Equal(t, 0, status.StatusCode)
+	})
+}
@@ -0,0 +1,44 @@
+package restyclient
+
+import (
+	"context"
+
+	"github.com/zhangzqs/go-typed-rpc/handler"
+)
+
+// PaginatorFunc 从 first 指定的页开始依次请求所有分页，将每页的元素逐个交给 yield
+// yield 返回错误时停止遍历并返回该错误
+type PaginatorFunc[I, Item any] func(ctx context.Context, first I, yield func(item Item) error) error
+
+// NewPaginator 基于单页请求 base 创建自动翻页的客户端
+// extract 从每页响应中取出元素、下一页的请求参数以及是否还有下一页；hasMore 为 false 时停止，
+// 请求失败或 ctx 被取消时停止并返回对应的错误（取消时返回 ctx.Err()）
+// 适用场景：调用方需要遍历分页接口的全部数据，无需手写翻页循环
+func NewPaginator[I, O, Item any](
+	base handler.HandlerFunc[I, O],
+	extract func(O) (items []Item, nextPage I, hasMore bool),
+) PaginatorFunc[I, Item] {
+	return func(ctx context.Context, first I, yield func(item Item) error) error {
+		page := first
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			resp, err := base(ctx, page)
+			if err != nil {
+				return err
+			}
+
+			items, next, hasMore := extract(resp)
+			for _, item := range items {
+				if err := yield(item); err != nil {
+					return err
+				}
+			}
+			if !hasMore {
+				return nil
+			}
+			page = next
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package restyclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"resty.dev/v3"
+)
+
+type listUsersRequest struct {
+	Page int `query:"page"`
+}
+
+type listUsersResponse struct {
+	Items    []TestResponse `json:"items"`
+	NextPage int            `json:"next_page"`
+	HasMore  bool           `json:"has_more"`
+}
+
+// TestNewPaginator tests following pages until the server reports no more items
+func TestNewPaginator(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		resp := listUsersResponse{NextPage: page + 1, HasMore: page < 3}
+		for i := 1; i <= 2; i++ {
+			id := int64((page-1)*2 + i)
+			resp.Items = append(resp.Items, TestResponse{ID: id, Name: "user" + strconv.FormatInt(id, 10)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	list := NewClient[listUsersRequest, listUsersResponse](resty.New().SetBaseURL(server.URL), http.MethodGet, "/users")
+	paginate := NewPaginator(list, func(resp listUsersResponse) ([]TestResponse, listUsersRequest, bool) {
+		return resp.Items, listUsersRequest{Page: resp.NextPage}, resp.HasMore
+	})
+
+	t.Run("all_pages", func(t *testing.T) {
+		requests = 0
+		var ids []int64
+
+		err := paginate(context.Background(), listUsersRequest{Page: 1}, func(item TestResponse) error {
+			ids = append(ids, item.ID)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3, 4, 5, 6}, ids)
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("yield_error_stops", func(t *testing.T) {
+		requests = 0
+		stop := errors.New("stop")
+
+		err := paginate(context.Background(), listUsersRequest{Page: 1}, func(item TestResponse) error {
+			if item.ID == 3 {
+				return stop
+			}
+			return nil
+		})
+
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("context_canceled", func(t *testing.T) {
+		requests = 0
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := paginate(ctx, listUsersRequest{Page: 1}, func(item TestResponse) error {
+			cancel()
+			return nil
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, requests)
+	})
+}
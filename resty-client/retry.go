@@ -0,0 +1,37 @@
+package restyclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"syscall"
+)
+
+// WithConnResetRetry 幂等请求（GET、HEAD、OPTIONS、TRACE、PUT、DELETE）在连接被对端重置时最多重试 maxRetries 次
+// 只针对 connection reset by peer，与按状态码重试无关；流式请求体（io.Reader 输入）无法重新发送，不会重试
+func WithConnResetRetry(maxRetries int) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.connResetRetries = maxRetries
+	}
+}
+
+// IsConnReset 判断错误是否由连接被对端重置（ECONNRESET）引起
+func IsConnReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET)
+}
+
+// canRetryConnReset 判断请求失败后是否可以因连接重置而重试
+func canRetryConnReset(method string, input any, err error) bool {
+	if !IsConnReset(err) {
+		return false
+	}
+	if _, ok := input.(io.Reader); ok {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
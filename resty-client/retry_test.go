@@ -0,0 +1,81 @@
+package restyclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"resty.dev/v3"
+)
+
+// newResetServer 启动一个前 resets 次请求直接重置连接、之后正常响应的服务
+func newResetServer(t *testing.T, resets int32) (*httptest.Server, *atomic.Int32) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= resets {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			// SO_LINGER 为 0 时关闭连接会发送 RST
+			conn.(*net.TCPConn).SetLinger(0)
+			conn.Close()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"name":"ok","email":""}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &attempts
+}
+
+// TestWithConnResetRetry tests retrying idempotent requests after a connection reset
+func TestWithConnResetRetry(t *testing.T) {
+	t.Run("retry_get", func(t *testing.T) {
+		server, attempts := newResetServer(t, 1)
+		client := resty.New().SetBaseURL(server.URL)
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/1", WithConnResetRetry(1))
+
+		result, err := get(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", result.Name)
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+
+	t.Run("retries_exhausted", func(t *testing.T) {
+		server, attempts := newResetServer(t, 3)
+		client := resty.New().SetBaseURL(server.URL)
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/1", WithConnResetRetry(1))
+
+		_, err := get(context.Background())
+
+		assert.True(t, IsConnReset(err))
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+
+	t.Run("no_retry_without_option", func(t *testing.T) {
+		server, attempts := newResetServer(t, 1)
+		client := resty.New().SetBaseURL(server.URL)
+
+		_, err := NewGetter[TestResponse](client, http.MethodGet, "/users/1")(context.Background())
+
+		assert.True(t, IsConnReset(err))
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("no_retry_for_post", func(t *testing.T) {
+		server, attempts := newResetServer(t, 1)
+		client := resty.New().SetBaseURL(server.URL)
+		create := NewClient[TestRequest, TestResponse](client, http.MethodPost, "/users", WithConnResetRetry(1))
+
+		_, err := create(context.Background(), TestRequest{Name: "Alice"})
+
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+}
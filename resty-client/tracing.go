@@ -0,0 +1,49 @@
+package restyclient
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/zhangzqs/go-typed-rpc/handler"
+	"resty.dev/v3"
+)
+
+// WithTracing 按 cfg 传递请求 ID，与 ginserver.WithTracing 使用同一份配置即可让一个请求 ID 贯穿客户端和服务端
+// 请求 ID 取自 ctx（handler.ContextWithRequestID 或服务端 WithTracing 注入），没有时为每次调用随机生成，
+// 以 cfg.Header 指定的请求头发送，重试时保持不变；cfg.Logger 非 nil 时每次调用输出一条以 cfg.LogKey 记录请求 ID 的日志
+func WithTracing(cfg handler.TracingConfig) ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.tracing = &cfg
+	}
+}
+
+// tracingContext 确保 ctx 携带请求 ID
+func tracingContext(ctx context.Context) context.Context {
+	if handler.RequestIDFromContext(ctx) != "" {
+		return ctx
+	}
+	return handler.ContextWithRequestID(ctx, handler.NewRequestID())
+}
+
+// logClientRequest 输出一次客户端调用的日志，请求失败或状态码为 4xx/5xx 时使用 Warn 级别
+func logClientRequest(ctx context.Context, cfg *handler.TracingConfig, method, url string, resp *resty.Response, err error, start time.Time) {
+	level := slog.LevelInfo
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("url", url),
+		slog.Duration("duration", time.Since(start)),
+		slog.String(cfg.LogKeyName(), handler.RequestIDFromContext(ctx)),
+	}
+	if resp != nil && resp.StatusCode() != 0 {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode()))
+		if resp.IsError() {
+			level = slog.LevelWarn
+		}
+	}
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	cfg.Logger.LogAttrs(ctx, level, "client request", attrs...)
+}
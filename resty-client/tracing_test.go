@@ -0,0 +1,52 @@
+package restyclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zhangzqs/go-typed-rpc/handler"
+	"resty.dev/v3"
+)
+
+// TestWithTracing tests sending the request ID header and logging it
+func TestWithTracing(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("X-Trace")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"name":"Alice"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	cfg := handler.TracingConfig{Header: "X-Trace", Logger: slog.New(slog.NewJSONHandler(&logs, nil))}
+	get := NewGetter[TestResponse](resty.New().SetBaseURL(server.URL), http.MethodGet, "/users/1", WithTracing(cfg))
+
+	t.Run("from_context", func(t *testing.T) {
+		logs.Reset()
+		ctx := handler.ContextWithRequestID(context.Background(), "req-1")
+
+		_, err := get(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "req-1", received)
+
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+		assert.Equal(t, "req-1", record[handler.DefaultRequestIDLogKey])
+		assert.Equal(t, float64(http.StatusOK), record["status"])
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		_, err := get(context.Background())
+
+		assert.NoError(t, err)
+		assert.Len(t, received, 32)
+	})
+}
@@ -0,0 +1,51 @@
+package restyclient
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"resty.dev/v3"
+)
+
+// WithTypedError 将错误响应（4xx/5xx）的响应体反序列化为 E 并作为错误返回，调用方可通过 errors.As 取得服务端的结构化错误
+// E 实现 error 时返回 E，否则 *E 实现 error 时返回 *E；响应体为空、无法反序列化或反序列化结果为零值时，回退为响应状态文本的错误
+// 请求错误的处理与 DefaultErrorHandler 一致；设置了 WithJSONCodec 时使用其反序列化函数
+func WithTypedError[E any]() ClientOptionFunc {
+	return func(opts *ClientOptions) {
+		opts.errorHandler = func(resp *resty.Response, err error) error {
+			if err != nil {
+				return ClassifyError(err)
+			}
+			if !resp.IsError() {
+				return nil
+			}
+			unmarshal := opts.jsonUnmarshal
+			if unmarshal == nil {
+				unmarshal = json.Unmarshal
+			}
+			if typedErr := decodeTypedError[E](resp.Bytes(), unmarshal); typedErr != nil {
+				return typedErr
+			}
+			return errors.New(resp.Status())
+		}
+	}
+}
+
+// decodeTypedError 将响应体反序列化为 E，无法得到有效的错误时返回 nil
+func decodeTypedError[E any](body []byte, unmarshal func(data []byte, v any) error) error {
+	if len(body) == 0 {
+		return nil
+	}
+	e := new(E)
+	if err := unmarshal(body, e); err != nil || reflect.ValueOf(e).Elem().IsZero() {
+		return nil
+	}
+	if typedErr, ok := any(*e).(error); ok {
+		return typedErr
+	}
+	if typedErr, ok := any(e).(error); ok {
+		return typedErr
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package restyclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"resty.dev/v3"
+)
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+type valueError struct {
+	Reason string `json:"reason"`
+}
+
+func (e valueError) Error() string {
+	return e.Reason
+}
+
+// TestWithTypedError tests decoding error response bodies into a typed error
+func TestWithTypedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/users/1":
+			w.Write([]byte(`{"id":1,"name":"Alice"}`))
+		case "/users/2":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"USER_NOT_FOUND","message":"user 2 not found"}`))
+		case "/users/3":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`<html>bad gateway</html>`))
+		case "/users/4":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"unexpected":true}`))
+		case "/users/5":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"reason":"duplicate"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := resty.New().SetBaseURL(server.URL)
+
+	t.Run("success", func(t *testing.T) {
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/1", WithTypedError[apiError]())
+
+		result, err := get(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", result.Name)
+	})
+
+	t.Run("typed_error", func(t *testing.T) {
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/2", WithTypedError[apiError]())
+
+		_, err := get(context.Background())
+
+		var apiErr *apiError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, "USER_NOT_FOUND", apiErr.Code)
+		assert.Equal(t, "user 2 not found", apiErr.Message)
+	})
+
+	t.Run("value_receiver_error", func(t *testing.T) {
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/5", WithTypedError[valueError]())
+
+		_, err := get(context.Background())
+
+		var valErr valueError
+		assert.True(t, errors.As(err, &valErr))
+		assert.Equal(t, "duplicate", valErr.Reason)
+	})
+
+	t.Run("fallback_non_json", func(t *testing.T) {
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/3", WithTypedError[apiError]())
+
+		_, err := get(context.Background())
+
+		assert.EqualError(t, err, "502 Bad Gateway")
+	})
+
+	t.Run("fallback_unexpected_shape", func(t *testing.T) {
+		get := NewGetter[TestResponse](client, http.MethodGet, "/users/4", WithTypedError[apiError]())
+
+		_, err := get(context.Background())
+
+		assert.EqualError(t, err, "400 Bad Request")
+	})
+}